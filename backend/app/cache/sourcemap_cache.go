@@ -3,20 +3,42 @@ package cache
 import (
 	"container/list"
 	"sync"
+
+	"github.com/go-sourcemap/sourcemap"
 )
 
 type sourceMapCacheEntry struct {
-	key  string
-	data []byte
+	key      string
+	consumer *sourcemap.Consumer
+	size     int64
+}
+
+// SourceMapCacheStats reports the usage of the source map cache since startup
+type SourceMapCacheStats struct {
+	Entries   int   `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+	MaxCount  int   `json:"maxCount"`
+	MaxBytes  int64 `json:"maxBytes"`
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	// Skipped counts the source maps too large to be cached at all
+	Skipped int64 `json:"skipped"`
 }
 
+// sourceMapCache holds parsed source maps by storage key, least recently used first out.
+// Sizes are estimates of the parsed consumer, not of the file.
 type sourceMapCache struct {
-	maxCount int
-	maxBytes int64
-	mu       sync.Mutex
-	items    map[string]*list.Element
-	order    *list.List
-	curBytes int64
+	maxCount  int
+	maxBytes  int64
+	mu        sync.Mutex
+	items     map[string]*list.Element
+	order     *list.List
+	curBytes  int64
+	hits      int64
+	misses    int64
+	evictions int64
+	skipped   int64
 }
 
 var SourceMapCache *sourceMapCache
@@ -30,33 +52,41 @@ func InitSourceMapCache(maxCount int, maxBytes int64) {
 	}
 }
 
-func (c *sourceMapCache) Get(key string) ([]byte, bool) {
+func (c *sourceMapCache) Get(key string) (*sourcemap.Consumer, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if el, ok := c.items[key]; ok {
 		c.order.MoveToFront(el)
-		return el.Value.(*sourceMapCacheEntry).data, true
+		c.hits++
+		return el.Value.(*sourceMapCacheEntry).consumer, true
 	}
+	c.misses++
 	return nil, false
 }
 
-func (c *sourceMapCache) Put(key string, data []byte) {
+// Put caches a parsed source map with its estimated size, a map larger than the whole cache isn't kept
+func (c *sourceMapCache) Put(key string, consumer *sourcemap.Consumer, size int64) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	dataSize := int64(len(data))
+	if size > c.maxBytes {
+		c.skipped++
+		c.remove(key)
+		return
+	}
 
 	if el, ok := c.items[key]; ok {
-		c.curBytes -= int64(len(el.Value.(*sourceMapCacheEntry).data))
-		el.Value.(*sourceMapCacheEntry).data = data
-		c.curBytes += dataSize
+		entry := el.Value.(*sourceMapCacheEntry)
+		c.curBytes += size - entry.size
+		entry.consumer = consumer
+		entry.size = size
 		c.order.MoveToFront(el)
 	} else {
-		entry := &sourceMapCacheEntry{key: key, data: data}
+		entry := &sourceMapCacheEntry{key: key, consumer: consumer, size: size}
 		el := c.order.PushFront(entry)
 		c.items[key] = el
-		c.curBytes += dataSize
+		c.curBytes += size
 	}
 
 	for c.order.Len() > c.maxCount || c.curBytes > c.maxBytes {
@@ -66,6 +96,39 @@ func (c *sourceMapCache) Put(key string, data []byte) {
 		}
 		evicted := c.order.Remove(back).(*sourceMapCacheEntry)
 		delete(c.items, evicted.key)
-		c.curBytes -= int64(len(evicted.data))
+		c.curBytes -= evicted.size
+		c.evictions++
+	}
+}
+
+// Delete drops a cached source map, used when the file at its storage key is replaced or removed
+func (c *sourceMapCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+}
+
+func (c *sourceMapCache) Stats() SourceMapCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return SourceMapCacheStats{
+		Entries:   c.order.Len(),
+		Bytes:     c.curBytes,
+		MaxCount:  c.maxCount,
+		MaxBytes:  c.maxBytes,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Skipped:   c.skipped,
+	}
+}
+
+func (c *sourceMapCache) remove(key string) {
+	if el, ok := c.items[key]; ok {
+		evicted := c.order.Remove(el).(*sourceMapCacheEntry)
+		delete(c.items, key)
+		c.curBytes -= evicted.size
 	}
 }
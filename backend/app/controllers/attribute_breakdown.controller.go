@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/repositories"
+	"backend/app/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	traceway "go.tracewayapp.com"
+)
+
+const (
+	defaultBreakdownLimit = 10
+	maxBreakdownLimit     = 100
+)
+
+type attributeBreakdownController struct{}
+
+type AttributeBreakdownRequest struct {
+	FromDate      time.Time `json:"fromDate"`
+	ToDate        time.Time `json:"toDate"`
+	Source        string    `json:"source" binding:"required,oneof=endpoint task exception"`
+	Key           string    `json:"key" binding:"required"`
+	Name          string    `json:"name"`          // optional endpoint or task name
+	StatusCodes   []string  `json:"statusCodes"`   // optional, endpoints only: 404, 5xx or 400-499
+	ExceptionHash string    `json:"exceptionHash"` // optional, exceptions only
+	Limit         int       `json:"limit"`         // top values, defaults to 10
+	models.TelemetryFilter
+}
+
+// GetBreakdown groups endpoints, tasks or exception occurrences by an attribute, e.g. the 5xx of POST /orders per tenant
+func (a attributeBreakdownController) GetBreakdown(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request AttributeBreakdownRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.Limit <= 0 {
+		request.Limit = defaultBreakdownLimit
+	}
+	request.Limit = min(request.Limit, maxBreakdownLimit)
+
+	search := models.TraceSearchQuery{Name: request.Name, TelemetryFilter: request.TelemetryFilter}
+	for _, value := range request.StatusCodes {
+		statusCodes, err := services.ParseStatusCodeRange(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		search.StatusCodes = append(search.StatusCodes, statusCodes)
+	}
+
+	var aliases map[string]string
+	if request.Source != models.BreakdownSourceEndpoint && request.Source != models.BreakdownSourceTask {
+		aliases, err = loadExceptionGroupAliases(c, projectId)
+		if err != nil {
+			c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception group aliases: %w", err))
+			return
+		}
+		// a hash merged into another group breaks down the whole group
+		request.ExceptionHash = services.CanonicalExceptionHash(aliases, request.ExceptionHash)
+	}
+
+	var breakdown *models.AttributeBreakdown
+	span := traceway.StartSpan(c, "loading attribute breakdown")
+	switch request.Source {
+	case models.BreakdownSourceEndpoint:
+		breakdown, err = repositories.AttributeBreakdownRepository.FindEndpointBreakdown(c, projectId, request.Key, request.FromDate, request.ToDate, search, request.Limit)
+	case models.BreakdownSourceTask:
+		breakdown, err = repositories.AttributeBreakdownRepository.FindTaskBreakdown(c, projectId, request.Key, request.FromDate, request.ToDate, search, request.Limit)
+	default:
+		breakdown, err = repositories.AttributeBreakdownRepository.FindExceptionBreakdown(c, projectId, request.Key, request.FromDate, request.ToDate, request.ExceptionHash, aliases, request.TelemetryFilter, request.Limit)
+	}
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading attribute breakdown: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+var AttributeBreakdownController = attributeBreakdownController{}
@@ -10,13 +10,9 @@ import (
 	"backend/app/services"
 	"backend/app/storage"
 	"context"
-	"crypto/sha256"
 	"database/sql"
-	"encoding/hex"
 	"fmt"
 	"net/http"
-	"regexp"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,6 +26,7 @@ type ReportRequest struct {
 	CollectionFrames []*clientmodels.CollectionFrame `json:"collectionFrames"`
 	AppVersion       string                          `json:"appVersion"`
 	ServerName       string                          `json:"serverName"`
+	Environment      string                          `json:"environment"`
 }
 
 func (e clientController) Report(c *gin.Context) {
@@ -72,23 +69,36 @@ func (e clientController) Report(c *gin.Context) {
 	// Map frontend sessionRecordingId → backend-generated exception UUID
 	recordingIdToExceptionId := map[string]uuid.UUID{}
 
+	// shared by all the stack traces of the report so each source map is parsed once
+	stackTraceResolver := services.NewStackTraceResolver()
+
 	for _, cf := range request.CollectionFrames {
 		for _, ct := range cf.Traces {
 			if ct.IsTask {
 				t := ct.ToTask(request.AppVersion, request.ServerName)
 				t.ProjectId = projectId
+				t.Environment = request.Environment
 				tasksToInsert = append(tasksToInsert, t)
 			} else {
 				e := ct.ToEndpoint(request.AppVersion, request.ServerName)
 				e.ProjectId = projectId
+				e.Environment = request.Environment
 				endpointsToInsert = append(endpointsToInsert, e)
 			}
 
+			traceSpans := make([]models.Span, 0, len(ct.Spans))
 			for _, cs := range ct.Spans {
 				span := cs.ToSpan(ct.ParsedId())
 				span.ProjectId = projectId
-				spansToInsert = append(spansToInsert, span)
+				span.Environment = request.Environment
+				// database wrappers name their spans after the query
+				if services.LooksLikeSQL(span.Name) {
+					span.DbStatement = services.NormalizeSQL(span.Name)
+				}
+				traceSpans = append(traceSpans, span)
 			}
+			services.NestSpansByTime(traceSpans)
+			spansToInsert = append(spansToInsert, traceSpans...)
 		}
 		projectAsAny, projectExists := c.Get(middleware.ProjectContextKey)
 		var project *models.Project
@@ -101,10 +111,23 @@ func (e clientController) Report(c *gin.Context) {
 		var sourceMaps *[]*models.SourceMap
 		if project != nil && isJsFramework(project.Framework) {
 			sourceMapsLoaded, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
-				if request.AppVersion != "" {
-					return repositories.SourceMapRepository.FindByProjectAndVersion(tx, projectId, request.AppVersion)
+				sourceMaps, err := repositories.SourceMapRepository.FindForVersion(tx, projectId, request.AppVersion)
+				if err != nil {
+					return nil, err
+				}
+				// scripts built with debug IDs are matched to their source maps whatever version they were uploaded with
+				debugIdMaps := make([]map[string]string, len(cf.StackTraces))
+				for i, cst := range cf.StackTraces {
+					debugIdMaps[i] = cst.DebugIds
+				}
+				if debugIds := services.CollectDebugIds(debugIdMaps); len(debugIds) > 0 {
+					byDebugId, err := repositories.SourceMapRepository.FindByDebugIds(tx, projectId, debugIds)
+					if err != nil {
+						return nil, err
+					}
+					sourceMaps = append(sourceMaps, byDebugId...)
 				}
-				return repositories.SourceMapRepository.FindLatestByProject(tx, projectId)
+				return sourceMaps, nil
 			})
 			if err == nil && len(sourceMapsLoaded) > 0 {
 				sourceMaps = &sourceMapsLoaded
@@ -114,12 +137,23 @@ func (e clientController) Report(c *gin.Context) {
 		for _, cst := range cf.StackTraces {
 			resolvedStackTrace := cst.StackTrace
 			if sourceMaps != nil {
-				resolvedStackTrace = services.ResolveStackTrace(c, projectId, cst.StackTrace, *sourceMaps)
+				resolvedStackTrace = stackTraceResolver.Resolve(c, cst.StackTrace, *sourceMaps, cst.DebugIds)
 			}
-			est := cst.ToExceptionStackTrace(ComputeExceptionHash(resolvedStackTrace, cst.IsMessage), request.AppVersion, request.ServerName)
+			est := cst.ToExceptionStackTrace(services.ComputeExceptionHash(resolvedStackTrace, cst.IsMessage), request.AppVersion, request.ServerName)
 			est.StackTrace = resolvedStackTrace
+			if resolvedStackTrace != cst.StackTrace {
+				// kept so the trace can be resolved again when source maps are re-uploaded
+				est.RawStackTrace = cst.StackTrace
+			}
 			est.Id = uuid.New()
 			est.ProjectId = projectId
+			est.Environment = request.Environment
+			if !cst.IsMessage {
+				parsed := services.ParseStackTrace(resolvedStackTrace)
+				est.ExceptionType = parsed.Type
+				est.ExceptionMessage = parsed.Message
+				est.Frames = parsed.Frames
+			}
 			if cst.SessionRecordingId != nil {
 				recordingIdToExceptionId[*cst.SessionRecordingId] = est.Id
 			}
@@ -129,6 +163,7 @@ func (e clientController) Report(c *gin.Context) {
 		for _, cm := range cf.Metrics {
 			mr := cm.ToMetricRecord(request.ServerName)
 			mr.ProjectId = projectId
+			mr.Environment = request.Environment
 			metricRecordsToInsert = append(metricRecordsToInsert, mr)
 		}
 
@@ -225,42 +260,6 @@ func (e clientController) Report(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-var (
-	errorMessageRe = regexp.MustCompile(`(?m)^(\*?[\w.]+):\s*.+`)
-	absolutePathRe = regexp.MustCompile(`/[^\s:]+/([^/\s:]+:\d+)`)
-	versionRe      = regexp.MustCompile(`@v[\d.]+`)
-	hexRe          = regexp.MustCompile(`0x[0-9a-fA-F]+`)
-	uuidRe         = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
-	largeNumberRe  = regexp.MustCompile(`(^|[^:\d])(\d{5,})($|[^\d])`)
-	emailRe        = regexp.MustCompile(`[\w.\-]+@[\w.\-]+\.\w+`)
-	ipRe           = regexp.MustCompile(`\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}(:\d+)?`)
-	goroutineRe    = regexp.MustCompile(`goroutine \d+`)
-	spacesRe       = regexp.MustCompile(`[ \t]+`)
-	newlinesRe     = regexp.MustCompile(`\n+`)
-)
-
-func ComputeExceptionHash(stackTrace string, isMessage bool) string {
-	normalized := stackTrace
-
-	if !isMessage {
-		normalized = errorMessageRe.ReplaceAllString(normalized, "$1")
-		normalized = absolutePathRe.ReplaceAllString(normalized, "$1")
-		normalized = versionRe.ReplaceAllString(normalized, "")
-		normalized = hexRe.ReplaceAllString(normalized, "<hex>")
-		normalized = uuidRe.ReplaceAllString(normalized, "<uuid>")
-		normalized = largeNumberRe.ReplaceAllString(normalized, "${1}<id>${3}")
-		normalized = emailRe.ReplaceAllString(normalized, "<email>")
-		normalized = ipRe.ReplaceAllString(normalized, "<ip>")
-		normalized = goroutineRe.ReplaceAllString(normalized, "goroutine <n>")
-		normalized = spacesRe.ReplaceAllString(normalized, " ")
-		normalized = newlinesRe.ReplaceAllString(normalized, "\n")
-	}
-
-	normalized = strings.TrimSpace(normalized)
-	hash := sha256.Sum256([]byte(normalized))
-	return hex.EncodeToString(hash[:])[:16]
-}
-
 var jsFrameworks = map[string]bool{
 	"react":   true,
 	"svelte":  true,
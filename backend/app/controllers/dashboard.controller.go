@@ -3,6 +3,7 @@ package controllers
 import (
 	"backend/app/models"
 	"backend/app/repositories"
+	"backend/app/services"
 	"net/http"
 	"sort"
 	"strings"
@@ -30,10 +31,23 @@ func (d dashboardController) GetDashboardOverview(c *gin.Context) {
 
 	now := time.Now()
 	start := now.Add(-24 * time.Hour)
+	filter := parseTelemetryFilter(c)
+
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
+
+	aliases, err := loadExceptionGroupAliases(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception group aliases: %w", err))
+		return
+	}
 
 	// Get last 10 issues in the last 24 hours (only exceptions, not messages)
 	span := traceway.StartSpan(c, "loading recent issues")
-	recentIssues, _, err := repositories.ExceptionStackTraceRepository.FindGrouped(c, projectId, start, now, 1, 10, "last_seen", "", "issues", false)
+	recentIssues, _, err := repositories.ExceptionStackTraceRepository.FindGrouped(c, projectId, start, now, 1, 10, "last_seen", "", "issues", "", false, aliases, settings.UserIdentityAttribute, filter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading recent issues: %w", err))
@@ -42,7 +56,7 @@ func (d dashboardController) GetDashboardOverview(c *gin.Context) {
 
 	// Get 10 worst performing endpoints
 	span = traceway.StartSpan(c, "loading worst endpoints")
-	worstEndpoints, err := repositories.EndpointRepository.FindWorstEndpoints(c, projectId, start, now, 10)
+	worstEndpoints, err := repositories.EndpointRepository.FindWorstEndpoints(c, projectId, start, now, 10, settings.ApdexThresholdMs, filter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading worst endpoints: %w", err))
@@ -54,8 +68,8 @@ func (d dashboardController) GetDashboardOverview(c *gin.Context) {
 		// Check if project has received ANY data (all time, not just 24h)
 		var epoch time.Time // zero time = beginning of time
 		span = traceway.StartSpan(c, "checking project data")
-		endpointCount, _ := repositories.EndpointRepository.CountBetween(c, projectId, epoch, now)
-		exceptionCount, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, epoch, now)
+		endpointCount, _ := repositories.EndpointRepository.CountBetween(c, projectId, epoch, now, models.TelemetryFilter{})
+		exceptionCount, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, epoch, now, models.TelemetryFilter{})
 		span.End()
 		hasData = endpointCount > 0 || exceptionCount > 0
 	}
@@ -73,12 +87,19 @@ func (d dashboardController) GetDashboard(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
 		return
 	}
+
+	thresholds, err := loadHealthProfile(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading health thresholds: %w", err))
+		return
+	}
 	serversParam := c.Query("servers")
 
 	var selectedServers []string
 	if serversParam != "" {
 		selectedServers = strings.Split(serversParam, ",")
 	}
+	filter := parseTelemetryFilter(c)
 
 	now := time.Now()
 	var start, end time.Time
@@ -112,7 +133,7 @@ func (d dashboardController) GetDashboard(c *gin.Context) {
 	intervalMinutes := calculateIntervalMinutes(duration)
 
 	// Get available servers in the time range
-	availableServers, err := repositories.MetricRecordRepository.GetDistinctServers(c, projectId, start, end)
+	availableServers, err := repositories.MetricRecordRepository.GetDistinctServers(c, projectId, start, end, filter)
 	if err != nil {
 		availableServers = []string{}
 	}
@@ -121,133 +142,133 @@ func (d dashboardController) GetDashboard(c *gin.Context) {
 
 	// 1. Requests count
 	span := traceway.StartSpan(c, "loading requests trend")
-	requestsTrend, err := repositories.EndpointRepository.CountByInterval(c, projectId, start, end, intervalMinutes)
+	requestsTrend, err := repositories.EndpointRepository.CountByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading requestsTrend: %w", err))
 		return
 	}
-	requestsCurrent, _ := repositories.EndpointRepository.CountBetween(c, projectId, start, end)
-	requestsPrev, _ := repositories.EndpointRepository.CountBetween(c, projectId, prevStart, prevEnd)
+	requestsCurrent, _ := repositories.EndpointRepository.CountBetween(c, projectId, start, end, filter)
+	requestsPrev, _ := repositories.EndpointRepository.CountBetween(c, projectId, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetric("requests", "Requests", float64(requestsCurrent), "count", requestsTrend, float64(requestsPrev), "requests"))
+	metrics = append(metrics, buildMetric("requests", "Requests", float64(requestsCurrent), "count", requestsTrend, float64(requestsPrev), "requests", thresholds))
 
 	// 2. Exceptions count
 	span = traceway.StartSpan(c, "loading exceptions trend")
-	exceptionsTrend, err := repositories.ExceptionStackTraceRepository.CountByInterval(c, projectId, start, end, intervalMinutes)
+	exceptionsTrend, err := repositories.ExceptionStackTraceRepository.CountByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exceptionsTrend: %w", err))
 		return
 	}
-	exceptionsCurrent, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, start, end)
-	exceptionsPrev, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, prevStart, prevEnd)
+	exceptionsCurrent, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, start, end, filter)
+	exceptionsPrev, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetric("exceptions", "Exceptions", float64(exceptionsCurrent), "count", exceptionsTrend, float64(exceptionsPrev), "exceptions"))
+	metrics = append(metrics, buildMetric("exceptions", "Exceptions", float64(exceptionsCurrent), "count", exceptionsTrend, float64(exceptionsPrev), "exceptions", thresholds))
 
 	// 3. Average Response Time
 	span = traceway.StartSpan(c, "loading avg response time")
-	avgDurationTrend, err := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, start, end, intervalMinutes)
+	avgDurationTrend, err := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading avgDurationTrend: %w", err))
 		return
 	}
 	avgDurationCurrent := getLastValue(avgDurationTrend)
-	avgDurationPrevTrend, _ := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, prevStart, prevEnd, intervalMinutes)
+	avgDurationPrevTrend, _ := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, prevStart, prevEnd, intervalMinutes, filter)
 	avgDurationPrev := getAverageValue(avgDurationPrevTrend)
 	span.End()
-	metrics = append(metrics, buildMetric("avg_response_time", "Avg Response Time", avgDurationCurrent, "ms", avgDurationTrend, avgDurationPrev, "response_time"))
+	metrics = append(metrics, buildMetric("avg_response_time", "Avg Response Time", avgDurationCurrent, "ms", avgDurationTrend, avgDurationPrev, "response_time", thresholds))
 
 	// 4. Error Rate
 	span = traceway.StartSpan(c, "loading error rate")
-	errorRateTrend, err := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, start, end, intervalMinutes)
+	errorRateTrend, err := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading errorRateTrend: %w", err))
 		return
 	}
 	errorRateCurrent := getLastValue(errorRateTrend)
-	errorRatePrevTrend, _ := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, prevStart, prevEnd, intervalMinutes)
+	errorRatePrevTrend, _ := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, prevStart, prevEnd, intervalMinutes, filter)
 	errorRatePrev := getAverageValue(errorRatePrevTrend)
 	span.End()
-	metrics = append(metrics, buildMetric("error_rate", "Error Rate", errorRateCurrent, "%", errorRateTrend, errorRatePrev, "error_rate"))
+	metrics = append(metrics, buildMetric("error_rate", "Error Rate", errorRateCurrent, "%", errorRateTrend, errorRatePrev, "error_rate", thresholds))
 
 	// 5. CPU Usage
 	span = traceway.StartSpan(c, "loading cpu usage")
-	cpuPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameCpuUsage, start, end, intervalMinutes, selectedServers)
+	cpuPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameCpuUsage, start, end, intervalMinutes, selectedServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading cpuPerServer: %w", err))
 		return
 	}
-	cpuPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, prevStart, prevEnd)
+	cpuPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("cpu_usage", "CPU Usage", "%", cpuPerServer, cpuPrev, "cpu"))
+	metrics = append(metrics, buildMetricWithServers("cpu_usage", "CPU Usage", "%", cpuPerServer, cpuPrev, "cpu", thresholds))
 
 	// 6. Memory Usage (MB)
 	span = traceway.StartSpan(c, "loading memory usage")
-	memPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryUsage, start, end, intervalMinutes, selectedServers)
+	memPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryUsage, start, end, intervalMinutes, selectedServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading memPerServer: %w", err))
 		return
 	}
-	memPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, prevStart, prevEnd)
+	memPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("memory_usage", "Memory Usage", "MB", memPerServer, memPrev, "memory"))
+	metrics = append(metrics, buildMetricWithServers("memory_usage", "Memory Usage", "MB", memPerServer, memPrev, "memory", thresholds))
 
 	// 7. Total System Memory (MB)
 	span = traceway.StartSpan(c, "loading total memory")
-	memTotalPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryTotal, start, end, intervalMinutes, selectedServers)
+	memTotalPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryTotal, start, end, intervalMinutes, selectedServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading memTotalPerServer: %w", err))
 		return
 	}
-	memTotalPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryTotal, prevStart, prevEnd)
+	memTotalPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryTotal, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("memory_total", "Total Memory", "MB", memTotalPerServer, memTotalPrev, "memory_total"))
+	metrics = append(metrics, buildMetricWithServers("memory_total", "Total Memory", "MB", memTotalPerServer, memTotalPrev, "memory_total", thresholds))
 
 	// 8. Go Routines
 	span = traceway.StartSpan(c, "loading go routines")
-	goRoutinesPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGoRoutines, start, end, intervalMinutes, selectedServers)
+	goRoutinesPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGoRoutines, start, end, intervalMinutes, selectedServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading goRoutinesPerServer: %w", err))
 		return
 	}
-	goRoutinesPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGoRoutines, prevStart, prevEnd)
+	goRoutinesPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGoRoutines, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("go_routines", "Go Routines", "", goRoutinesPerServer, goRoutinesPrev, "go_routines"))
+	metrics = append(metrics, buildMetricWithServers("go_routines", "Go Routines", "", goRoutinesPerServer, goRoutinesPrev, "go_routines", thresholds))
 
 	// 9. Heap Objects
 	span = traceway.StartSpan(c, "loading heap objects")
-	heapObjectsPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameHeapObjects, start, end, intervalMinutes, selectedServers)
+	heapObjectsPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameHeapObjects, start, end, intervalMinutes, selectedServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading heapObjectsPerServer: %w", err))
 		return
 	}
-	heapObjectsPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameHeapObjects, prevStart, prevEnd)
+	heapObjectsPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameHeapObjects, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("heap_objects", "Heap Objects", "", heapObjectsPerServer, heapObjectsPrev, "heap_objects"))
+	metrics = append(metrics, buildMetricWithServers("heap_objects", "Heap Objects", "", heapObjectsPerServer, heapObjectsPrev, "heap_objects", thresholds))
 
 	// 10. Num GC
 	span = traceway.StartSpan(c, "loading gc cycles")
-	numGCPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameNumGC, start, end, intervalMinutes, selectedServers)
+	numGCPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameNumGC, start, end, intervalMinutes, selectedServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading numGCPerServer: %w", err))
 		return
 	}
-	numGCPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameNumGC, prevStart, prevEnd)
+	numGCPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameNumGC, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("num_gc", "GC Cycles", "", numGCPerServer, numGCPrev, "num_gc"))
+	metrics = append(metrics, buildMetricWithServers("num_gc", "GC Cycles", "", numGCPerServer, numGCPrev, "num_gc", thresholds))
 
 	// 11. GC Pause Total (convert from nanoseconds to milliseconds)
 	span = traceway.StartSpan(c, "loading gc pause")
-	gcPausePerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGCPauseTotal, start, end, intervalMinutes, selectedServers)
+	gcPausePerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGCPauseTotal, start, end, intervalMinutes, selectedServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading gcPausePerServer: %w", err))
@@ -259,19 +280,26 @@ func (d dashboardController) GetDashboard(c *gin.Context) {
 			gcPausePerServer[serverName][i].Value = points[i].Value / 1_000_000
 		}
 	}
-	gcPausePrevRaw, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGCPauseTotal, prevStart, prevEnd)
+	gcPausePrevRaw, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGCPauseTotal, prevStart, prevEnd, filter)
 	span.End()
 	gcPausePrev := gcPausePrevRaw / 1_000_000
-	metrics = append(metrics, buildMetricWithServers("gc_pause", "GC Pause", "ms", gcPausePerServer, gcPausePrev, "gc_pause"))
+	metrics = append(metrics, buildMetricWithServers("gc_pause", "GC Pause", "ms", gcPausePerServer, gcPausePrev, "gc_pause", thresholds))
+
+	deployMarkers, err := loadDeployMarkers(c, projectId, start, end)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
 
 	c.JSON(http.StatusOK, models.DashboardResponse{
 		Metrics:          metrics,
 		AvailableServers: availableServers,
 		LastUpdated:      now,
+		DeployMarkers:    deployMarkers,
 	})
 }
 
-func buildMetric(id, name string, current float64, unit string, trend []models.TimeSeriesPoint, prev float64, metricType string) models.DashboardMetric {
+func buildMetric(id, name string, current float64, unit string, trend []models.TimeSeriesPoint, prev float64, metricType string, thresholds models.HealthThresholdProfile) models.DashboardMetric {
 	// Append unit to name if meaningful (not empty and not "count")
 	displayName := name
 	if unit != "" && unit != "count" {
@@ -294,7 +322,7 @@ func buildMetric(id, name string, current float64, unit string, trend []models.T
 	}
 
 	// Determine status based on metric type
-	status := calculateStatus(current, metricType)
+	status := services.HealthStatus(current, metricType, thresholds)
 
 	return models.DashboardMetric{
 		ID:        id,
@@ -307,7 +335,7 @@ func buildMetric(id, name string, current float64, unit string, trend []models.T
 	}
 }
 
-func buildMetricWithServers(id, name, unit string, serverData map[string][]models.TimeSeriesPoint, prev float64, metricType string) models.DashboardMetric {
+func buildMetricWithServers(id, name, unit string, serverData map[string][]models.TimeSeriesPoint, prev float64, metricType string, thresholds models.HealthThresholdProfile) models.DashboardMetric {
 	// Append unit to name if meaningful (not empty and not "count")
 	displayName := name
 	if unit != "" && unit != "count" {
@@ -385,7 +413,7 @@ func buildMetricWithServers(id, name, unit string, serverData map[string][]model
 	}
 
 	// Determine status based on metric type
-	status := calculateStatus(aggregateValue, metricType)
+	status := services.HealthStatus(aggregateValue, metricType, thresholds)
 
 	return models.DashboardMetric{
 		ID:        id,
@@ -399,82 +427,6 @@ func buildMetricWithServers(id, name, unit string, serverData map[string][]model
 	}
 }
 
-func calculateStatus(value float64, metricType string) string {
-	switch metricType {
-	case "requests":
-		// Lower is worse for requests (less traffic may indicate issues)
-		if value < 10 {
-			return "critical"
-		} else if value < 100 {
-			return "warning"
-		}
-		return "healthy"
-	case "exceptions":
-		// Higher is worse for exceptions
-		if value > 50 {
-			return "critical"
-		} else if value > 10 {
-			return "warning"
-		}
-		return "healthy"
-	case "response_time":
-		// Higher is worse for response time
-		if value > 500 {
-			return "critical"
-		} else if value > 200 {
-			return "warning"
-		}
-		return "healthy"
-	case "error_rate":
-		// Higher is worse for error rate
-		if value > 5 {
-			return "critical"
-		} else if value > 2 {
-			return "warning"
-		}
-		return "healthy"
-	case "cpu":
-		// Higher is worse for CPU
-		if value > 90 {
-			return "critical"
-		} else if value > 70 {
-			return "warning"
-		}
-		return "healthy"
-	case "memory":
-		// Higher is worse for memory (MB)
-		if value > 900 {
-			return "critical"
-		} else if value > 700 {
-			return "warning"
-		}
-		return "healthy"
-	case "memory_total":
-		// Total memory is informational - always healthy
-		return "healthy"
-	case "go_routines":
-		// Higher may indicate goroutine leaks
-		if value > 10000 {
-			return "critical"
-		} else if value > 5000 {
-			return "warning"
-		}
-		return "healthy"
-	case "heap_objects":
-		// Higher may indicate memory pressure
-		if value > 1000000 {
-			return "critical"
-		} else if value > 500000 {
-			return "warning"
-		}
-		return "healthy"
-	case "num_gc", "gc_pause":
-		// These are informational, always healthy
-		return "healthy"
-	}
-	return "healthy"
-}
-
 func getLastValue(points []models.TimeSeriesPoint) float64 {
 	if len(points) == 0 {
 		return 0
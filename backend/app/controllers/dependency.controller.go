@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/repositories"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	traceway "go.tracewayapp.com"
+)
+
+type dependencyController struct{}
+
+type DependencySearchRequest struct {
+	FromDate time.Time `json:"fromDate"`
+	ToDate   time.Time `json:"toDate"`
+	Search   string    `json:"search"` // matches the host or the route
+	models.TelemetryFilter
+}
+
+type DependencySeriesRequest struct {
+	FromDate time.Time `json:"fromDate"`
+	ToDate   time.Time `json:"toDate"`
+	Host     string    `json:"host" binding:"required"`
+	Route    string    `json:"route"` // optional, every route of the host when empty
+	models.TelemetryFilter
+}
+
+type DependencySeriesResponse struct {
+	IntervalMinutes int                                `json:"intervalMinutes"`
+	Series          []models.DependencyTimeSeriesPoint `json:"series"`
+}
+
+// FindOutbound lists the downstream HTTP hosts and routes the project calls
+func (d dependencyController) FindOutbound(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request DependencySearchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span := traceway.StartSpan(c, "loading outbound dependencies")
+	dependencies, err := repositories.DependencyRepository.FindOutbound(c, projectId, request.FromDate, request.ToDate, request.Search, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading outbound dependencies: %w", err))
+		return
+	}
+	if dependencies == nil {
+		dependencies = []models.OutboundDependency{}
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// GetSeries returns the call volume, error rate and p95 latency of a downstream host over time
+func (d dependencyController) GetSeries(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request DependencySeriesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	intervalMinutes := calculateIntervalMinutes(request.ToDate.Sub(request.FromDate))
+
+	span := traceway.StartSpan(c, "loading dependency series")
+	points, err := repositories.DependencyRepository.SeriesByInterval(c, projectId, request.Host, request.Route, request.FromDate, request.ToDate, intervalMinutes, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading dependency series: %w", err))
+		return
+	}
+	if points == nil {
+		points = []models.DependencyTimeSeriesPoint{}
+	}
+
+	c.JSON(http.StatusOK, DependencySeriesResponse{
+		IntervalMinutes: intervalMinutes,
+		Series:          points,
+	})
+}
+
+var DependencyController = dependencyController{}
@@ -23,6 +23,7 @@ type EndpointSearchRequest struct {
 	SortDirection string           `json:"sortDirection"`
 	Pagination    PaginationParams `json:"pagination"`
 	Search        string           `json:"search"`
+	models.TelemetryFilter
 }
 
 type EndpointInstancesRequest struct {
@@ -31,6 +32,7 @@ type EndpointInstancesRequest struct {
 	OrderBy       string           `json:"orderBy"`
 	SortDirection string           `json:"sortDirection"`
 	Pagination    PaginationParams `json:"pagination"`
+	models.TelemetryFilter
 }
 
 type EndpointInstancesResponse struct {
@@ -39,11 +41,46 @@ type EndpointInstancesResponse struct {
 	Pagination Pagination                  `json:"pagination"`
 }
 
+type EndpointApdexRequest struct {
+	FromDate time.Time `json:"fromDate"`
+	ToDate   time.Time `json:"toDate"`
+	Endpoint string    `json:"endpoint"` // optional, all endpoints when empty
+	models.TelemetryFilter
+}
+
+type EndpointApdexResponse struct {
+	ApdexThresholdMs int                      `json:"apdexThresholdMs"`
+	Series           []models.TimeSeriesPoint `json:"series"`
+	DeployMarkers    []models.DeployMarker    `json:"deployMarkers"`
+}
+
 type EndpointStackedChartRequest struct {
 	FromDate        time.Time `json:"fromDate"`
 	ToDate          time.Time `json:"toDate"`
 	MetricType      string    `json:"metricType"`      // total_time, p50, p95, p99
 	IntervalMinutes int       `json:"intervalMinutes"` // bucket size
+	models.TelemetryFilter
+}
+
+type EndpointSpanBreakdownRequest struct {
+	FromDate time.Time `json:"fromDate"`
+	ToDate   time.Time `json:"toDate"`
+	Endpoint string    `json:"endpoint" binding:"required"`
+	models.TelemetryFilter
+}
+
+type EndpointSeriesRequest struct {
+	FromDate time.Time `json:"fromDate"`
+	ToDate   time.Time `json:"toDate"`
+	Endpoint string    `json:"endpoint" binding:"required"`
+	models.TelemetryFilter
+}
+
+// PerformanceSeriesResponse is shared by the endpoint and task detail trends
+type PerformanceSeriesResponse struct {
+	IntervalMinutes int                             `json:"intervalMinutes"`
+	Series          []models.PerformanceSeriesPoint `json:"series"`
+	DeployMarkers   []models.DeployMarker           `json:"deployMarkers"`
 }
 
 func (e endpointController) FindAllEndpoints(c *gin.Context) {
@@ -60,7 +97,7 @@ func (e endpointController) FindAllEndpoints(c *gin.Context) {
 	}
 
 	span := traceway.StartSpan(c, "loading endpoints")
-	endpoints, total, err := repositories.EndpointRepository.FindAll(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy)
+	endpoints, total, err := repositories.EndpointRepository.FindAll(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading endpoints: %w", err))
@@ -91,8 +128,14 @@ func (e endpointController) FindGroupedByEndpoint(c *gin.Context) {
 		return
 	}
 
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
+
 	span := traceway.StartSpan(c, "loading grouped endpoints")
-	stats, total, err := repositories.EndpointRepository.FindGroupedByEndpoint(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection, request.Search)
+	stats, total, err := repositories.EndpointRepository.FindGroupedByEndpoint(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection, request.Search, settings.ApdexThresholdMs, settings.UserIdentityAttribute, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading stats: %w", err))
@@ -136,7 +179,7 @@ func (e endpointController) FindByEndpoint(c *gin.Context) {
 	}
 
 	span := traceway.StartSpan(c, "loading endpoint instances")
-	endpoints, total, err := repositories.EndpointRepository.FindByEndpoint(c, projectId, endpoint, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection)
+	endpoints, total, err := repositories.EndpointRepository.FindByEndpoint(c, projectId, endpoint, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading endpoints: %w", err))
@@ -144,8 +187,13 @@ func (e endpointController) FindByEndpoint(c *gin.Context) {
 	}
 
 	// Get aggregate stats for this endpoint
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
 	span = traceway.StartSpan(c, "loading endpoint stats")
-	stats, err := repositories.EndpointRepository.GetEndpointStats(c, projectId, endpoint, request.FromDate, request.ToDate)
+	stats, err := repositories.EndpointRepository.GetEndpointStats(c, projectId, endpoint, request.FromDate, request.ToDate, settings.ApdexThresholdMs, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		// Don't fail the request if stats fail, just return nil stats
@@ -188,16 +236,132 @@ func (e endpointController) GetStackedChart(c *gin.Context) {
 	}
 
 	span := traceway.StartSpan(c, "loading stacked chart")
-	data, err := repositories.EndpointRepository.GetEndpointStackedChart(c, projectId, request.FromDate, request.ToDate, request.IntervalMinutes, request.MetricType)
+	data, err := repositories.EndpointRepository.GetEndpointStackedChart(c, projectId, request.FromDate, request.ToDate, request.IntervalMinutes, request.MetricType, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading stacked chart data: %w", err))
 		return
 	}
 
+	data.DeployMarkers, err = loadDeployMarkers(c, projectId, request.FromDate, request.ToDate)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
+
 	c.JSON(http.StatusOK, data)
 }
 
+func (e endpointController) GetApdexSeries(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request EndpointApdexRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
+
+	intervalMinutes := calculateIntervalMinutes(request.ToDate.Sub(request.FromDate))
+
+	span := traceway.StartSpan(c, "loading apdex series")
+	series, err := repositories.EndpointRepository.ApdexByInterval(c, projectId, request.Endpoint, request.FromDate, request.ToDate, intervalMinutes, settings.ApdexThresholdMs, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading apdex series: %w", err))
+		return
+	}
+	if series == nil {
+		series = []models.TimeSeriesPoint{}
+	}
+
+	deployMarkers, err := loadDeployMarkers(c, projectId, request.FromDate, request.ToDate)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, EndpointApdexResponse{
+		ApdexThresholdMs: settings.ApdexThresholdMs,
+		Series:           series,
+		DeployMarkers:    deployMarkers,
+	})
+}
+
+// GetPerformanceSeries returns the p50/p95/p99, throughput and error rate trend of a single endpoint
+func (e endpointController) GetPerformanceSeries(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request EndpointSeriesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	intervalMinutes := calculateIntervalMinutes(request.ToDate.Sub(request.FromDate))
+
+	span := traceway.StartSpan(c, "loading endpoint performance series")
+	series, err := repositories.EndpointRepository.PerformanceByInterval(c, projectId, request.Endpoint, request.FromDate, request.ToDate, intervalMinutes, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading endpoint performance series: %w", err))
+		return
+	}
+	if series == nil {
+		series = []models.PerformanceSeriesPoint{}
+	}
+
+	deployMarkers, err := loadDeployMarkers(c, projectId, request.FromDate, request.ToDate)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, PerformanceSeriesResponse{
+		IntervalMinutes: intervalMinutes,
+		Series:          series,
+		DeployMarkers:   deployMarkers,
+	})
+}
+
+// GetSpanBreakdown shows which spans the time of an endpoint goes to, complementing the detail stats
+func (e endpointController) GetSpanBreakdown(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request EndpointSpanBreakdownRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span := traceway.StartSpan(c, "loading span breakdown")
+	breakdown, err := repositories.EndpointRepository.GetSpanBreakdown(c, projectId, request.Endpoint, request.FromDate, request.ToDate, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading span breakdown: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
 func (e endpointController) GetSlowEndpoint(c *gin.Context) {
 	projectId, err := middleware.GetProjectId(c)
 	if err != nil {
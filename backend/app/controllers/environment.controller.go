@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/repositories"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	traceway "go.tracewayapp.com"
+)
+
+type environmentController struct{}
+
+// List returns the environments the project reported telemetry for, used to populate the environment selector
+func (e environmentController) List(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	now := time.Now()
+	start, end := parseTimeRange(c, now)
+	if c.Query("fromDate") == "" {
+		start = now.Add(-30 * 24 * time.Hour)
+	}
+
+	span := traceway.StartSpan(c, "loading environments")
+	environments, err := repositories.EnvironmentRepository.FindDistinct(c, projectId, start, end)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading environments: %w", err))
+		return
+	}
+	if environments == nil {
+		environments = []string{}
+	}
+
+	c.JSON(http.StatusOK, environments)
+}
+
+var EnvironmentController = environmentController{}
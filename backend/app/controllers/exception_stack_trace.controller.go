@@ -3,7 +3,9 @@ package controllers
 import (
 	"backend/app/middleware"
 	"backend/app/models"
+	"backend/app/pgdb"
 	"backend/app/repositories"
+	"backend/app/services"
 	"backend/app/storage"
 	"database/sql"
 	"encoding/json"
@@ -27,21 +29,34 @@ type ExceptionSearchRequest struct {
 	Pagination      PaginationParams `json:"pagination"`
 	Search          string           `json:"search"`
 	SearchType      string           `json:"searchType"`
+	ExceptionType   string           `json:"exceptionType"`
 	IncludeArchived bool             `json:"includeArchived"`
+	models.TelemetryFilter
 }
 
 type ArchiveRequest struct {
 	Hashes []string `json:"hashes"`
 }
 
+type MergeExceptionsRequest struct {
+	TargetHash string   `json:"targetHash" binding:"required"`
+	Hashes     []string `json:"hashes" binding:"required,min=1,dive,required"`
+}
+
+type UnmergeExceptionsRequest struct {
+	Hashes []string `json:"hashes" binding:"required,min=1,dive,required"`
+}
+
 type ExceptionDetailRequest struct {
 	Pagination PaginationParams `json:"pagination"`
+	models.TelemetryFilter
 }
 
 type ExceptionDetailResponse struct {
 	Group                  *models.ExceptionGroup       `json:"group"`
 	Occurrences            []models.ExceptionStackTrace `json:"occurrences"`
 	Pagination             Pagination                   `json:"pagination"`
+	Facets                 []models.ExceptionFacet      `json:"facets"`
 	SessionRecordingEvents json.RawMessage              `json:"sessionRecordingEvents,omitempty"`
 }
 
@@ -58,8 +73,20 @@ func (e exceptionStackTraceController) FindGrouppedExceptionStackTraces(c *gin.C
 		return
 	}
 
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
+
+	aliases, err := loadExceptionGroupAliases(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception group aliases: %w", err))
+		return
+	}
+
 	span := traceway.StartSpan(c, "loading grouped exceptions")
-	exceptions, total, err := repositories.ExceptionStackTraceRepository.FindGrouped(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.Search, request.SearchType, request.IncludeArchived)
+	exceptions, total, err := repositories.ExceptionStackTraceRepository.FindGrouped(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.Search, request.SearchType, request.ExceptionType, request.IncludeArchived, aliases, settings.UserIdentityAttribute, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exceptions: %w", err))
@@ -77,7 +104,7 @@ func (e exceptionStackTraceController) FindGrouppedExceptionStackTraces(c *gin.C
 		start24h := now.Add(-24 * time.Hour)
 
 		span = traceway.StartSpan(c, "loading hourly trends")
-		trends, err := repositories.ExceptionStackTraceRepository.GetHourlyTrendForHashes(c, projectId, hashes, start24h, now)
+		trends, err := repositories.ExceptionStackTraceRepository.GetHourlyTrendForHashes(c, projectId, hashes, aliases, start24h, now, request.TelemetryFilter)
 		span.End()
 		if err != nil {
 			c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading trends: %w", err))
@@ -124,8 +151,16 @@ func (e exceptionStackTraceController) FindByHash(c *gin.Context) {
 		request.Pagination = PaginationParams{Page: 1, PageSize: 20}
 	}
 
+	aliases, err := loadExceptionGroupAliases(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception group aliases: %w", err))
+		return
+	}
+	// a hash merged into another group shows the group it was merged into
+	exceptionHash = services.CanonicalExceptionHash(aliases, exceptionHash)
+
 	span := traceway.StartSpan(c, "loading exception by hash")
-	group, occurrences, total, err := repositories.ExceptionStackTraceRepository.FindByHash(c, projectId, exceptionHash, request.Pagination.Page, request.Pagination.PageSize)
+	group, occurrences, total, err := repositories.ExceptionStackTraceRepository.FindByHash(c, projectId, exceptionHash, aliases, request.Pagination.Page, request.Pagination.PageSize, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading the group: %w", err))
@@ -137,9 +172,28 @@ func (e exceptionStackTraceController) FindByHash(c *gin.Context) {
 		return
 	}
 
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
+	resolveOnRead(c, projectId, occurrences)
+	for i := range occurrences {
+		services.MarkInAppFrames(occurrences[i].Frames, settings.InAppPrefixList())
+	}
+
+	span = traceway.StartSpan(c, "loading exception facets")
+	facets, err := repositories.ExceptionStackTraceRepository.FindFacets(c, projectId, exceptionHash, aliases, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception facets: %w", err))
+		return
+	}
+
 	response := ExceptionDetailResponse{
 		Group:       group,
 		Occurrences: occurrences,
+		Facets:      facets,
 		Pagination: Pagination{
 			Page:       request.Pagination.Page,
 			PageSize:   request.Pagination.PageSize,
@@ -183,7 +237,19 @@ func (e exceptionStackTraceController) ArchiveExceptions(c *gin.Context) {
 		return
 	}
 
-	err = repositories.ExceptionStackTraceRepository.ArchiveByHashes(c, projectId, request.Hashes)
+	aliases, err := loadExceptionGroupAliases(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception group aliases: %w", err))
+		return
+	}
+
+	// merged groups are archived under their canonical hash
+	hashes := make([]string, len(request.Hashes))
+	for i, hash := range request.Hashes {
+		hashes[i] = services.CanonicalExceptionHash(aliases, hash)
+	}
+
+	err = repositories.ExceptionStackTraceRepository.ArchiveByHashes(c, projectId, hashes)
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error archiving %s: %w", strings.Join(request.Hashes, ","), err))
 		return
@@ -210,7 +276,19 @@ func (e exceptionStackTraceController) UnarchiveExceptions(c *gin.Context) {
 		return
 	}
 
-	err = repositories.ExceptionStackTraceRepository.UnarchiveByHashes(c, projectId, request.Hashes)
+	aliases, err := loadExceptionGroupAliases(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception group aliases: %w", err))
+		return
+	}
+
+	// also clear archives left on hashes from before they were merged
+	var hashes []string
+	for _, hash := range request.Hashes {
+		hashes = append(hashes, services.ExceptionGroupMembers(aliases, hash)...)
+	}
+
+	err = repositories.ExceptionStackTraceRepository.UnarchiveByHashes(c, projectId, hashes)
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error unarchiving %s: %w", strings.Join(request.Hashes, ","), err))
 		return
@@ -219,6 +297,72 @@ func (e exceptionStackTraceController) UnarchiveExceptions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"unarchived": len(request.Hashes)})
 }
 
+func (e exceptionStackTraceController) MergeExceptions(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request MergeExceptionsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	canonical, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (string, error) {
+		aliases, err := repositories.ExceptionGroupAliasRepository.FindByProject(tx, projectId)
+		if err != nil {
+			return "", err
+		}
+		merged, err := services.MergeExceptionGroups(aliases, request.TargetHash, request.Hashes)
+		if err != nil {
+			return "", err
+		}
+		return services.CanonicalExceptionHash(aliases, request.TargetHash), repositories.ExceptionGroupAliasRepository.Save(tx, projectId, merged)
+	})
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error merging %s into %s: %w", strings.Join(request.Hashes, ","), request.TargetHash, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"canonicalHash": canonical})
+}
+
+func (e exceptionStackTraceController) UnmergeExceptions(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request UnmergeExceptionsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	unmerged, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]string, error) {
+		aliases, err := repositories.ExceptionGroupAliasRepository.FindByProject(tx, projectId)
+		if err != nil {
+			return nil, err
+		}
+		hashes := services.UnmergeExceptionHashes(aliases, request.Hashes)
+		for _, hash := range hashes {
+			if err := repositories.ExceptionGroupAliasRepository.Delete(tx, projectId, hash); err != nil {
+				return nil, err
+			}
+		}
+		return hashes, nil
+	})
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error unmerging %s: %w", strings.Join(request.Hashes, ","), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unmerged": unmerged})
+}
+
 func (e exceptionStackTraceController) FindById(c *gin.Context) {
 	projectId, err := middleware.GetProjectId(c)
 	if err != nil {
@@ -245,6 +389,16 @@ func (e exceptionStackTraceController) FindById(c *gin.Context) {
 		return
 	}
 
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
+	resolved := []models.ExceptionStackTrace{*exception}
+	resolveOnRead(c, projectId, resolved)
+	exception = &resolved[0]
+	services.MarkInAppFrames(exception.Frames, settings.InAppPrefixList())
+
 	response := gin.H{"exception": exception}
 
 	filePath, err := repositories.SessionRecordingRepository.FindByExceptionId(c, projectId, exceptionId)
@@ -262,4 +416,49 @@ func (e exceptionStackTraceController) FindById(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// resolveOnRead symbolicates exceptions recorded before the source maps of their version were uploaded.
+// The stored hash is kept, the source map resolution job moves the exceptions to their new group.
+func resolveOnRead(c *gin.Context, projectId uuid.UUID, exceptions []models.ExceptionStackTrace) {
+	hashes := make([]string, len(exceptions))
+	for i := range exceptions {
+		hashes[i] = exceptions[i].ExceptionHash
+	}
+
+	span := traceway.StartSpan(c, "resolving source maps")
+	services.ResolveExceptions(c, projectId, exceptions, func(version string) []*models.SourceMap {
+		if version == "" {
+			return nil
+		}
+		sourceMaps, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
+			return repositories.SourceMapRepository.FindByProjectAndVersion(tx, projectId, version)
+		})
+		if err != nil {
+			traceway.CaptureException(fmt.Errorf("failed to load source maps of version %s: %w", version, err))
+		}
+		return sourceMaps
+	}, func(debugIds []string) []*models.SourceMap {
+		sourceMaps, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
+			return repositories.SourceMapRepository.FindByDebugIds(tx, projectId, debugIds)
+		})
+		if err != nil {
+			traceway.CaptureException(fmt.Errorf("failed to load source maps by debug ID: %w", err))
+		}
+		return sourceMaps
+	})
+	span.End()
+
+	for i := range exceptions {
+		exceptions[i].ExceptionHash = hashes[i]
+	}
+}
+
+func loadExceptionGroupAliases(c *gin.Context, projectId uuid.UUID) (map[string]string, error) {
+	span := traceway.StartSpan(c, "loading exception group aliases")
+	aliases, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (map[string]string, error) {
+		return repositories.ExceptionGroupAliasRepository.FindByProject(tx, projectId)
+	})
+	span.End()
+	return aliases, err
+}
+
 var ExceptionStackTraceController = exceptionStackTraceController{}
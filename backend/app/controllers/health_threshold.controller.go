@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	traceway "go.tracewayapp.com"
+)
+
+type healthThresholdController struct{}
+
+type HealthThresholdInput struct {
+	MetricType string  `json:"metricType" binding:"required"`
+	Warning    float64 `json:"warning"`
+	Critical   float64 `json:"critical"`
+}
+
+type UpdateHealthThresholdsRequest struct {
+	Thresholds []HealthThresholdInput `json:"thresholds" binding:"required"`
+}
+
+func (h healthThresholdController) GetHealthThresholds(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	overrides, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.HealthThreshold, error) {
+		return repositories.HealthThresholdRepository.FindByProject(tx, projectId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading health thresholds: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, buildHealthThresholdResponse(overrides))
+}
+
+func (h healthThresholdController) UpdateHealthThresholds(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request UpdateHealthThresholdsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, t := range request.Thresholds {
+		if err := validateHealthThreshold(t); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	overrides, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.HealthThreshold, error) {
+		for _, t := range request.Thresholds {
+			if err := repositories.HealthThresholdRepository.Upsert(tx, projectId, t.MetricType, t.Warning, t.Critical); err != nil {
+				return nil, err
+			}
+		}
+		return repositories.HealthThresholdRepository.FindByProject(tx, projectId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error saving health thresholds: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, buildHealthThresholdResponse(overrides))
+}
+
+// ResetHealthThreshold removes the project override for a single metric type so the default applies again
+func (h healthThresholdController) ResetHealthThreshold(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	metricType := c.Param("metricType")
+	if !models.IsHealthMetricType(metricType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown metric type: %s", metricType)})
+		return
+	}
+
+	overrides, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.HealthThreshold, error) {
+		if err := repositories.HealthThresholdRepository.DeleteByProjectAndMetricType(tx, projectId, metricType); err != nil {
+			return nil, err
+		}
+		return repositories.HealthThresholdRepository.FindByProject(tx, projectId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error resetting health threshold: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, buildHealthThresholdResponse(overrides))
+}
+
+func validateHealthThreshold(t HealthThresholdInput) error {
+	if !models.IsHealthMetricType(t.MetricType) {
+		return fmt.Errorf("Unknown metric type: %s", t.MetricType)
+	}
+	if t.Warning < 0 || t.Critical < 0 {
+		return fmt.Errorf("Thresholds for %s must not be negative", t.MetricType)
+	}
+	if models.IsLowerWorseHealthMetric(t.MetricType) {
+		if t.Critical > t.Warning {
+			return fmt.Errorf("Critical threshold for %s must not be above the warning threshold", t.MetricType)
+		}
+	} else if t.Critical < t.Warning {
+		return fmt.Errorf("Critical threshold for %s must not be below the warning threshold", t.MetricType)
+	}
+	return nil
+}
+
+func buildHealthThresholdResponse(overrides []*models.HealthThreshold) []models.HealthThresholdResponse {
+	overridden := make(map[string]bool, len(overrides))
+	for _, o := range overrides {
+		overridden[o.MetricType] = true
+	}
+	profile := models.NewHealthThresholdProfile(overrides)
+
+	response := make([]models.HealthThresholdResponse, 0, len(models.HealthMetricTypes))
+	for _, metricType := range models.HealthMetricTypes {
+		item := models.HealthThresholdResponse{
+			MetricType:   metricType,
+			LowerIsWorse: models.IsLowerWorseHealthMetric(metricType),
+			IsDefault:    !overridden[metricType],
+		}
+		if levels, ok := profile[metricType]; ok {
+			warning, critical := levels.Warning, levels.Critical
+			item.Warning = &warning
+			item.Critical = &critical
+		}
+		response = append(response, item)
+	}
+	return response
+}
+
+// loadHealthProfile loads the effective health thresholds of a project for status calculation
+func loadHealthProfile(c *gin.Context, projectId uuid.UUID) (models.HealthThresholdProfile, error) {
+	span := traceway.StartSpan(c, "loading health thresholds")
+	profile, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (models.HealthThresholdProfile, error) {
+		return repositories.HealthThresholdRepository.LoadProfile(tx, projectId)
+	})
+	span.End()
+	return profile, err
+}
+
+var HealthThresholdController = healthThresholdController{}
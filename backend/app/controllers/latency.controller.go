@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/repositories"
+	"backend/app/services"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	traceway "go.tracewayapp.com"
+)
+
+type latencyController struct{}
+
+// maxLatencyRange bounds the time range of the latency distributions, the heatmap has a row per interval
+const maxLatencyRange = 31 * 24 * time.Hour
+
+type LatencyDistributionRequest struct {
+	FromDate time.Time `json:"fromDate" binding:"required"`
+	ToDate   time.Time `json:"toDate" binding:"required"`
+	Type     string    `json:"type" binding:"required,oneof=endpoint task"`
+	Name     string    `json:"name" binding:"required"` // endpoint or task name
+	models.TelemetryFilter
+}
+
+func (r LatencyDistributionRequest) validateRange() error {
+	if !r.FromDate.Before(r.ToDate) {
+		return errors.New("fromDate must be before toDate")
+	}
+	if r.ToDate.Sub(r.FromDate) > maxLatencyRange {
+		return errors.New("time range can't exceed 31 days")
+	}
+	return nil
+}
+
+// GetHistogram returns the log-scaled duration histogram of an endpoint or task
+func (l latencyController) GetHistogram(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request LatencyDistributionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := request.validateRange(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span := traceway.StartSpan(c, "loading latency histogram")
+	counts, err := repositories.LatencyRepository.FindBucketCounts(c, request.Type, projectId, request.Name, request.FromDate, request.ToDate, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading latency histogram: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, services.BuildLatencyHistogram(counts))
+}
+
+// GetHeatmap returns the time by latency matrix of an endpoint or task
+func (l latencyController) GetHeatmap(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request LatencyDistributionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := request.validateRange(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	intervalMinutes := calculateIntervalMinutes(request.ToDate.Sub(request.FromDate))
+
+	span := traceway.StartSpan(c, "loading latency heatmap")
+	cells, err := repositories.LatencyRepository.FindHeatmapCells(c, request.Type, projectId, request.Name, request.FromDate, request.ToDate, intervalMinutes, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading latency heatmap: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, services.BuildLatencyHeatmap(cells, request.FromDate, request.ToDate, intervalMinutes))
+}
+
+var LatencyController = latencyController{}
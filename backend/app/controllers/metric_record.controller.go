@@ -4,6 +4,7 @@ import (
 	"backend/app/middleware"
 	"backend/app/models"
 	"backend/app/repositories"
+	"backend/app/services"
 	"net/http"
 	"time"
 
@@ -20,20 +21,27 @@ func (e metricRecordController) FindHomepageStats(c *gin.Context) {
 		return
 	}
 
+	thresholds, err := loadHealthProfile(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading health thresholds: %w", err))
+		return
+	}
+
 	// requests in last 24h vs previous 24h
 	now := time.Now()
 	oneDayAgo := now.Add(-24 * time.Hour)
 	twoDaysAgo := now.Add(-48 * time.Hour)
+	filter := parseTelemetryFilter(c)
 
 	// requests
 	span := traceway.StartSpan(c, "loading requests stats")
-	requestsNow, err := repositories.EndpointRepository.CountBetween(c, projectId, oneDayAgo, now)
+	requestsNow, err := repositories.EndpointRepository.CountBetween(c, projectId, oneDayAgo, now, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading requestsNow: %w", err))
 		return
 	}
-	requestsPrev, err := repositories.EndpointRepository.CountBetween(c, projectId, twoDaysAgo, oneDayAgo)
+	requestsPrev, err := repositories.EndpointRepository.CountBetween(c, projectId, twoDaysAgo, oneDayAgo, filter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading requestsPrev: %w", err))
@@ -42,13 +50,13 @@ func (e metricRecordController) FindHomepageStats(c *gin.Context) {
 
 	// exceptions
 	span = traceway.StartSpan(c, "loading exceptions stats")
-	exceptionsNow, err := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, oneDayAgo, now)
+	exceptionsNow, err := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, oneDayAgo, now, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exceptionsNow: %w", err))
 		return
 	}
-	exceptionsPrev, err := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, twoDaysAgo, oneDayAgo)
+	exceptionsPrev, err := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, twoDaysAgo, oneDayAgo, filter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exceptionsPrev: %w", err))
@@ -57,13 +65,13 @@ func (e metricRecordController) FindHomepageStats(c *gin.Context) {
 
 	// ram usage last 24h vs previous 24h
 	span = traceway.StartSpan(c, "loading ram usage")
-	ramNow, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, oneDayAgo, now)
+	ramNow, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, oneDayAgo, now, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading ramNow: %w", err))
 		return
 	}
-	ramPrev, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, twoDaysAgo, oneDayAgo)
+	ramPrev, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, twoDaysAgo, oneDayAgo, filter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading ramPrev: %w", err))
@@ -72,13 +80,13 @@ func (e metricRecordController) FindHomepageStats(c *gin.Context) {
 
 	// memory usage last 24h vs previous 24h
 	span = traceway.StartSpan(c, "loading cpu usage")
-	cpuNow, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, oneDayAgo, now)
+	cpuNow, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, oneDayAgo, now, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading cpuNow: %w", err))
 		return
 	}
-	cpuPrev, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, twoDaysAgo, oneDayAgo)
+	cpuPrev, err := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, twoDaysAgo, oneDayAgo, filter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading cpuPrev: %w", err))
@@ -90,21 +98,25 @@ func (e metricRecordController) FindHomepageStats(c *gin.Context) {
 			Current:       float64(requestsNow),
 			Previous:      float64(requestsPrev),
 			PercentChange: calculatePercentChange(float64(requestsNow), float64(requestsPrev)),
+			Status:        services.HealthStatus(float64(requestsNow), "requests", thresholds),
 		},
 		Exceptions: StatsComparison{
 			Current:       float64(exceptionsNow),
 			Previous:      float64(exceptionsPrev),
 			PercentChange: calculatePercentChange(float64(exceptionsNow), float64(exceptionsPrev)),
+			Status:        services.HealthStatus(float64(exceptionsNow), "exceptions", thresholds),
 		},
 		MemoryUsage: StatsComparison{
 			Current:       ramNow,
 			Previous:      ramPrev,
 			PercentChange: calculatePercentChange(ramNow, ramPrev),
+			Status:        services.HealthStatus(ramNow, "memory", thresholds),
 		},
 		CpuUsage: StatsComparison{
 			Current:       cpuNow,
 			Previous:      cpuPrev,
 			PercentChange: calculatePercentChange(cpuNow, cpuPrev),
+			Status:        services.HealthStatus(cpuNow, "cpu", thresholds),
 		},
 	})
 }
@@ -120,6 +132,7 @@ type StatsComparison struct {
 	Current       float64 `json:"current"`
 	Previous      float64 `json:"previous"`
 	PercentChange float64 `json:"percentChange"`
+	Status        string  `json:"status"`
 }
 
 func calculatePercentChange(current, previous float64) float64 {
@@ -18,17 +18,20 @@ type ApplicationMetricsResponse struct {
 	Metrics          []models.DashboardMetric `json:"metrics"`
 	AvailableServers []string                 `json:"availableServers"`
 	LastUpdated      time.Time                `json:"lastUpdated"`
+	DeployMarkers    []models.DeployMarker    `json:"deployMarkers"`
 }
 
 type StatsMetricsResponse struct {
-	Metrics     []models.DashboardMetric `json:"metrics"`
-	LastUpdated time.Time                `json:"lastUpdated"`
+	Metrics       []models.DashboardMetric `json:"metrics"`
+	LastUpdated   time.Time                `json:"lastUpdated"`
+	DeployMarkers []models.DeployMarker    `json:"deployMarkers"`
 }
 
 type ServerMetricsResponse struct {
 	Metrics          []models.DashboardMetric `json:"metrics"`
 	AvailableServers []string                 `json:"availableServers"`
 	LastUpdated      time.Time                `json:"lastUpdated"`
+	DeployMarkers    []models.DeployMarker    `json:"deployMarkers"`
 }
 
 // GetApplicationMetrics returns Go application metrics (Go Routines, Heap Objects, GC Cycles, GC Pause)
@@ -39,8 +42,15 @@ func (m metricsController) GetApplicationMetrics(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
 		return
 	}
+
+	thresholds, err := loadHealthProfile(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading health thresholds: %w", err))
+		return
+	}
 	now := time.Now()
 	start, end := parseTimeRange(c, now)
+	filter := parseTelemetryFilter(c)
 
 	// Calculate previous period for comparison
 	duration := end.Sub(start)
@@ -51,7 +61,7 @@ func (m metricsController) GetApplicationMetrics(c *gin.Context) {
 	intervalMinutes := calculateIntervalMinutes(duration)
 
 	// Get available servers in the time range
-	availableServers, err := repositories.MetricRecordRepository.GetDistinctServers(c, projectId, start, end)
+	availableServers, err := repositories.MetricRecordRepository.GetDistinctServers(c, projectId, start, end, filter)
 	if err != nil {
 		availableServers = []string{}
 	}
@@ -63,43 +73,43 @@ func (m metricsController) GetApplicationMetrics(c *gin.Context) {
 
 	// 1. Go Routines
 	span := traceway.StartSpan(c, "loading go routines")
-	goRoutinesPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGoRoutines, start, end, intervalMinutes, emptyServers)
+	goRoutinesPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGoRoutines, start, end, intervalMinutes, emptyServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading goRoutinesPerServer: %w", err))
 		return
 	}
-	goRoutinesPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGoRoutines, prevStart, prevEnd)
+	goRoutinesPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGoRoutines, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("go_routines", "Go Routines", "", goRoutinesPerServer, goRoutinesPrev, "go_routines"))
+	metrics = append(metrics, buildMetricWithServers("go_routines", "Go Routines", "", goRoutinesPerServer, goRoutinesPrev, "go_routines", thresholds))
 
 	// 2. Heap Objects
 	span = traceway.StartSpan(c, "loading heap objects")
-	heapObjectsPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameHeapObjects, start, end, intervalMinutes, emptyServers)
+	heapObjectsPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameHeapObjects, start, end, intervalMinutes, emptyServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading heapObjectsPerServer: %w", err))
 		return
 	}
-	heapObjectsPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameHeapObjects, prevStart, prevEnd)
+	heapObjectsPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameHeapObjects, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("heap_objects", "Heap Objects", "", heapObjectsPerServer, heapObjectsPrev, "heap_objects"))
+	metrics = append(metrics, buildMetricWithServers("heap_objects", "Heap Objects", "", heapObjectsPerServer, heapObjectsPrev, "heap_objects", thresholds))
 
 	// 3. GC Cycles (Num GC)
 	span = traceway.StartSpan(c, "loading gc cycles")
-	numGCPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameNumGC, start, end, intervalMinutes, emptyServers)
+	numGCPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameNumGC, start, end, intervalMinutes, emptyServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading numGCPerServer: %w", err))
 		return
 	}
-	numGCPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameNumGC, prevStart, prevEnd)
+	numGCPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameNumGC, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("num_gc", "GC Cycles", "", numGCPerServer, numGCPrev, "num_gc"))
+	metrics = append(metrics, buildMetricWithServers("num_gc", "GC Cycles", "", numGCPerServer, numGCPrev, "num_gc", thresholds))
 
 	// 4. GC Pause Total (convert from nanoseconds to milliseconds)
 	span = traceway.StartSpan(c, "loading gc pause")
-	gcPausePerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGCPauseTotal, start, end, intervalMinutes, emptyServers)
+	gcPausePerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameGCPauseTotal, start, end, intervalMinutes, emptyServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading gcPausePerServer: %w", err))
@@ -111,15 +121,22 @@ func (m metricsController) GetApplicationMetrics(c *gin.Context) {
 			gcPausePerServer[serverName][i].Value = points[i].Value / 1_000_000
 		}
 	}
-	gcPausePrevRaw, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGCPauseTotal, prevStart, prevEnd)
+	gcPausePrevRaw, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameGCPauseTotal, prevStart, prevEnd, filter)
 	span.End()
 	gcPausePrev := gcPausePrevRaw / 1_000_000
-	metrics = append(metrics, buildMetricWithServers("gc_pause", "GC Pause", "ms", gcPausePerServer, gcPausePrev, "gc_pause"))
+	metrics = append(metrics, buildMetricWithServers("gc_pause", "GC Pause", "ms", gcPausePerServer, gcPausePrev, "gc_pause", thresholds))
+
+	deployMarkers, err := loadDeployMarkers(c, projectId, start, end)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
 
 	c.JSON(http.StatusOK, ApplicationMetricsResponse{
 		Metrics:          metrics,
 		AvailableServers: availableServers,
 		LastUpdated:      now,
+		DeployMarkers:    deployMarkers,
 	})
 }
 
@@ -131,8 +148,15 @@ func (m metricsController) GetStatsMetrics(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
 		return
 	}
+
+	thresholds, err := loadHealthProfile(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading health thresholds: %w", err))
+		return
+	}
 	now := time.Now()
 	start, end := parseTimeRange(c, now)
+	filter := parseTelemetryFilter(c)
 
 	// Calculate previous period for comparison
 	duration := end.Sub(start)
@@ -146,61 +170,68 @@ func (m metricsController) GetStatsMetrics(c *gin.Context) {
 
 	// 1. Requests count
 	span := traceway.StartSpan(c, "loading requests trend")
-	requestsTrend, err := repositories.EndpointRepository.CountByInterval(c, projectId, start, end, intervalMinutes)
+	requestsTrend, err := repositories.EndpointRepository.CountByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading requestsTrend: %w", err))
 		return
 	}
-	requestsCurrent, _ := repositories.EndpointRepository.CountBetween(c, projectId, start, end)
-	requestsPrev, _ := repositories.EndpointRepository.CountBetween(c, projectId, prevStart, prevEnd)
+	requestsCurrent, _ := repositories.EndpointRepository.CountBetween(c, projectId, start, end, filter)
+	requestsPrev, _ := repositories.EndpointRepository.CountBetween(c, projectId, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetric("requests", "Requests", float64(requestsCurrent), "count", requestsTrend, float64(requestsPrev), "requests"))
+	metrics = append(metrics, buildMetric("requests", "Requests", float64(requestsCurrent), "count", requestsTrend, float64(requestsPrev), "requests", thresholds))
 
 	// 2. Exceptions count
 	span = traceway.StartSpan(c, "loading exceptions trend")
-	exceptionsTrend, err := repositories.ExceptionStackTraceRepository.CountByInterval(c, projectId, start, end, intervalMinutes)
+	exceptionsTrend, err := repositories.ExceptionStackTraceRepository.CountByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exceptionsTrend: %w", err))
 		return
 	}
-	exceptionsCurrent, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, start, end)
-	exceptionsPrev, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, prevStart, prevEnd)
+	exceptionsCurrent, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, start, end, filter)
+	exceptionsPrev, _ := repositories.ExceptionStackTraceRepository.CountBetween(c, projectId, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetric("exceptions", "Exceptions", float64(exceptionsCurrent), "count", exceptionsTrend, float64(exceptionsPrev), "exceptions"))
+	metrics = append(metrics, buildMetric("exceptions", "Exceptions", float64(exceptionsCurrent), "count", exceptionsTrend, float64(exceptionsPrev), "exceptions", thresholds))
 
 	// 3. Average Response Time
 	span = traceway.StartSpan(c, "loading avg response time")
-	avgDurationTrend, err := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, start, end, intervalMinutes)
+	avgDurationTrend, err := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading avgDurationTrend: %w", err))
 		return
 	}
 	avgDurationCurrent := getLastValue(avgDurationTrend)
-	avgDurationPrevTrend, _ := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, prevStart, prevEnd, intervalMinutes)
+	avgDurationPrevTrend, _ := repositories.EndpointRepository.AvgDurationByInterval(c, projectId, prevStart, prevEnd, intervalMinutes, filter)
 	avgDurationPrev := getAverageValue(avgDurationPrevTrend)
 	span.End()
-	metrics = append(metrics, buildMetric("avg_response_time", "Avg Response Time", avgDurationCurrent, "ms", avgDurationTrend, avgDurationPrev, "response_time"))
+	metrics = append(metrics, buildMetric("avg_response_time", "Avg Response Time", avgDurationCurrent, "ms", avgDurationTrend, avgDurationPrev, "response_time", thresholds))
 
 	// 4. Error Rate
 	span = traceway.StartSpan(c, "loading error rate")
-	errorRateTrend, err := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, start, end, intervalMinutes)
+	errorRateTrend, err := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, start, end, intervalMinutes, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading errorRateTrend: %w", err))
 		return
 	}
 	errorRateCurrent := getLastValue(errorRateTrend)
-	errorRatePrevTrend, _ := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, prevStart, prevEnd, intervalMinutes)
+	errorRatePrevTrend, _ := repositories.EndpointRepository.ErrorRateByInterval(c, projectId, prevStart, prevEnd, intervalMinutes, filter)
 	errorRatePrev := getAverageValue(errorRatePrevTrend)
 	span.End()
-	metrics = append(metrics, buildMetric("error_rate", "Error Rate", errorRateCurrent, "%", errorRateTrend, errorRatePrev, "error_rate"))
+	metrics = append(metrics, buildMetric("error_rate", "Error Rate", errorRateCurrent, "%", errorRateTrend, errorRatePrev, "error_rate", thresholds))
+
+	deployMarkers, err := loadDeployMarkers(c, projectId, start, end)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
 
 	c.JSON(http.StatusOK, StatsMetricsResponse{
-		Metrics:     metrics,
-		LastUpdated: now,
+		Metrics:       metrics,
+		LastUpdated:   now,
+		DeployMarkers: deployMarkers,
 	})
 }
 
@@ -212,8 +243,15 @@ func (m metricsController) GetServerMetrics(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
 		return
 	}
+
+	thresholds, err := loadHealthProfile(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading health thresholds: %w", err))
+		return
+	}
 	now := time.Now()
 	start, end := parseTimeRange(c, now)
+	filter := parseTelemetryFilter(c)
 
 	// Calculate previous period for comparison
 	duration := end.Sub(start)
@@ -224,7 +262,7 @@ func (m metricsController) GetServerMetrics(c *gin.Context) {
 	intervalMinutes := calculateIntervalMinutes(duration)
 
 	// Get available servers in the time range
-	availableServers, err := repositories.MetricRecordRepository.GetDistinctServers(c, projectId, start, end)
+	availableServers, err := repositories.MetricRecordRepository.GetDistinctServers(c, projectId, start, end, filter)
 	if err != nil {
 		availableServers = []string{}
 	}
@@ -236,44 +274,51 @@ func (m metricsController) GetServerMetrics(c *gin.Context) {
 
 	// 1. CPU Usage
 	span := traceway.StartSpan(c, "loading cpu usage")
-	cpuPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameCpuUsage, start, end, intervalMinutes, emptyServers)
+	cpuPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameCpuUsage, start, end, intervalMinutes, emptyServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading cpuPerServer: %w", err))
 		return
 	}
-	cpuPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, prevStart, prevEnd)
+	cpuPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameCpuUsage, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("cpu_usage", "CPU Usage", "%", cpuPerServer, cpuPrev, "cpu"))
+	metrics = append(metrics, buildMetricWithServers("cpu_usage", "CPU Usage", "%", cpuPerServer, cpuPrev, "cpu", thresholds))
 
 	// 2. Memory Usage (MB)
 	span = traceway.StartSpan(c, "loading memory usage")
-	memPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryUsage, start, end, intervalMinutes, emptyServers)
+	memPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryUsage, start, end, intervalMinutes, emptyServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading memPerServer: %w", err))
 		return
 	}
-	memPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, prevStart, prevEnd)
+	memPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryUsage, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("memory_usage", "Memory Usage", "MB", memPerServer, memPrev, "memory"))
+	metrics = append(metrics, buildMetricWithServers("memory_usage", "Memory Usage", "MB", memPerServer, memPrev, "memory", thresholds))
 
 	// 3. Total System Memory (MB)
 	span = traceway.StartSpan(c, "loading total memory")
-	memTotalPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryTotal, start, end, intervalMinutes, emptyServers)
+	memTotalPerServer, err := repositories.MetricRecordRepository.GetAverageByIntervalPerServer(c, projectId, models.MetricNameMemoryTotal, start, end, intervalMinutes, emptyServers, filter)
 	if err != nil {
 		span.End()
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading memTotalPerServer: %w", err))
 		return
 	}
-	memTotalPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryTotal, prevStart, prevEnd)
+	memTotalPrev, _ := repositories.MetricRecordRepository.GetAverageBetween(c, projectId, models.MetricNameMemoryTotal, prevStart, prevEnd, filter)
 	span.End()
-	metrics = append(metrics, buildMetricWithServers("memory_total", "Total Memory", "MB", memTotalPerServer, memTotalPrev, "memory_total"))
+	metrics = append(metrics, buildMetricWithServers("memory_total", "Total Memory", "MB", memTotalPerServer, memTotalPrev, "memory_total", thresholds))
+
+	deployMarkers, err := loadDeployMarkers(c, projectId, start, end)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
 
 	c.JSON(http.StatusOK, ServerMetricsResponse{
 		Metrics:          metrics,
 		AvailableServers: availableServers,
 		LastUpdated:      now,
+		DeployMarkers:    deployMarkers,
 	})
 }
 
@@ -301,4 +346,13 @@ func parseTimeRange(c *gin.Context, now time.Time) (start, end time.Time) {
 	return start, end
 }
 
+// parseTelemetryFilter reads the optional telemetry filter from the query string,
+// resource attributes are passed as resource[host.name]=web-1
+func parseTelemetryFilter(c *gin.Context) models.TelemetryFilter {
+	return models.TelemetryFilter{
+		Environment:        c.Query("environment"),
+		ResourceAttributes: c.QueryMap("resource"),
+	}
+}
+
 var MetricsController = metricsController{}
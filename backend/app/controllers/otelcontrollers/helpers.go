@@ -1,11 +1,14 @@
 package otelcontrollers
 
 import (
+	"backend/app/services"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
 )
 
 // otelTraceIDToUUID converts a 16-byte OTEL trace ID to a UUID (direct byte mapping).
@@ -28,6 +31,102 @@ func otelSpanIDToUUID(spanID []byte) uuid.UUID {
 	return u
 }
 
+// spanKindName maps the OTLP span kind to the lowercase name stored with spans
+func spanKindName(kind tracepb.Span_SpanKind) string {
+	switch kind {
+	case tracepb.Span_SPAN_KIND_SERVER:
+		return "server"
+	case tracepb.Span_SPAN_KIND_CLIENT:
+		return "client"
+	case tracepb.Span_SPAN_KIND_PRODUCER:
+		return "producer"
+	case tracepb.Span_SPAN_KIND_CONSUMER:
+		return "consumer"
+	case tracepb.Span_SPAN_KIND_INTERNAL:
+		return "internal"
+	}
+	return ""
+}
+
+// getPeerService names the external dependency a client or producer span calls,
+// preferring the explicit peer.service over the database system and the remote address
+func getPeerService(kind tracepb.Span_SpanKind, attrs []*commonpb.KeyValue) string {
+	if kind != tracepb.Span_SPAN_KIND_CLIENT && kind != tracepb.Span_SPAN_KIND_PRODUCER {
+		return ""
+	}
+	for _, key := range []string{"peer.service", "db.system", "messaging.system", "server.address", "net.peer.name"} {
+		if value := getStringAttribute(attrs, key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// getDbSystem reads the database system, db.system.name replaced db.system in newer semantic conventions
+func getDbSystem(attrs []*commonpb.KeyValue) string {
+	if system := getStringAttribute(attrs, "db.system.name"); system != "" {
+		return system
+	}
+	return getStringAttribute(attrs, "db.system")
+}
+
+// getDbStatement returns the normalized query of a database client span, empty for other spans
+func getDbStatement(attrs []*commonpb.KeyValue) string {
+	statement := getStringAttribute(attrs, "db.query.text")
+	if statement == "" {
+		statement = getStringAttribute(attrs, "db.statement")
+	}
+	if statement == "" {
+		return ""
+	}
+	return services.NormalizeSQL(statement)
+}
+
+// getOutgoingHTTPCall reads the method, host, route template and status code of an outgoing HTTP call,
+// the method is empty for spans that aren't HTTP client calls
+func getOutgoingHTTPCall(kind tracepb.Span_SpanKind, attrs []*commonpb.KeyValue) (method, host, route string, statusCode int16) {
+	if kind != tracepb.Span_SPAN_KIND_CLIENT {
+		return "", "", "", 0
+	}
+	method = getStringAttribute(attrs, "http.request.method")
+	if method == "" {
+		method = getStringAttribute(attrs, "http.method")
+	}
+	if method == "" {
+		return "", "", "", 0
+	}
+
+	fullURL := getStringAttribute(attrs, "url.full")
+	if fullURL == "" {
+		fullURL = getStringAttribute(attrs, "http.url")
+	}
+	var parsed *url.URL
+	if fullURL != "" {
+		parsed, _ = url.Parse(fullURL)
+	}
+
+	host = getStringAttribute(attrs, "server.address")
+	if host == "" {
+		host = getStringAttribute(attrs, "net.peer.name")
+	}
+	if host == "" && parsed != nil {
+		host = parsed.Hostname()
+	}
+
+	route = getStringAttribute(attrs, "url.template")
+	if route == "" && parsed != nil {
+		route = services.TemplateURLPath(parsed.Path)
+	}
+
+	if code, ok := getIntAttribute(attrs, "http.response.status_code"); ok {
+		statusCode = int16(code)
+	} else if code, ok := getIntAttribute(attrs, "http.status_code"); ok {
+		statusCode = int16(code)
+	}
+
+	return method, host, route, statusCode
+}
+
 func nanoToTime(nanos uint64) time.Time {
 	return time.Unix(0, int64(nanos))
 }
@@ -66,6 +165,14 @@ func getStringAttribute(attrs []*commonpb.KeyValue, key string) string {
 	return ""
 }
 
+// getEnvironmentAttribute reads the deployment environment, preferring the current semantic convention key
+func getEnvironmentAttribute(attrs []*commonpb.KeyValue) string {
+	if env := getStringAttribute(attrs, "deployment.environment.name"); env != "" {
+		return env
+	}
+	return getStringAttribute(attrs, "deployment.environment")
+}
+
 func getIntAttribute(attrs []*commonpb.KeyValue, key string) (int64, bool) {
 	for _, kv := range attrs {
 		if kv.Key == key && kv.Value != nil {
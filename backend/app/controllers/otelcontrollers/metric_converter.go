@@ -17,6 +17,7 @@ func convertMetrics(projectId uuid.UUID, req *colmetricspb.ExportMetricsServiceR
 		if sn == "" {
 			sn = serverName
 		}
+		environment := getEnvironmentAttribute(resAttrs)
 
 		for _, sm := range rm.ScopeMetrics {
 			for _, metric := range sm.Metrics {
@@ -24,27 +25,29 @@ func convertMetrics(projectId uuid.UUID, req *colmetricspb.ExportMetricsServiceR
 
 				switch data := metric.Data.(type) {
 				case *metricspb.Metric_Gauge:
-					records = appendNumberDataPoints(records, projectId, name, sn, data.Gauge.GetDataPoints())
+					records = appendNumberDataPoints(records, projectId, name, sn, environment, data.Gauge.GetDataPoints())
 				case *metricspb.Metric_Sum:
-					records = appendNumberDataPoints(records, projectId, name, sn, data.Sum.GetDataPoints())
+					records = appendNumberDataPoints(records, projectId, name, sn, environment, data.Sum.GetDataPoints())
 				case *metricspb.Metric_Histogram:
 					for _, dp := range data.Histogram.GetDataPoints() {
 						ts := nanoToTime(dp.TimeUnixNano)
 						if dp.Count > 0 && dp.Sum != nil {
 							records = append(records, models.MetricRecord{
-								ProjectId:  projectId,
-								Name:       name + ".avg",
-								Value:      *dp.Sum / float64(dp.Count),
-								RecordedAt: ts,
-								ServerName: sn,
+								ProjectId:   projectId,
+								Name:        name + ".avg",
+								Value:       *dp.Sum / float64(dp.Count),
+								RecordedAt:  ts,
+								ServerName:  sn,
+								Environment: environment,
 							})
 						}
 						records = append(records, models.MetricRecord{
-							ProjectId:  projectId,
-							Name:       name + ".count",
-							Value:      float64(dp.Count),
-							RecordedAt: ts,
-							ServerName: sn,
+							ProjectId:   projectId,
+							Name:        name + ".count",
+							Value:       float64(dp.Count),
+							RecordedAt:  ts,
+							ServerName:  sn,
+							Environment: environment,
 						})
 					}
 				}
@@ -54,7 +57,7 @@ func convertMetrics(projectId uuid.UUID, req *colmetricspb.ExportMetricsServiceR
 	return records
 }
 
-func appendNumberDataPoints(records []models.MetricRecord, projectId uuid.UUID, name, serverName string, dps []*metricspb.NumberDataPoint) []models.MetricRecord {
+func appendNumberDataPoints(records []models.MetricRecord, projectId uuid.UUID, name, serverName, environment string, dps []*metricspb.NumberDataPoint) []models.MetricRecord {
 	for _, dp := range dps {
 		var value float64
 		switch v := dp.Value.(type) {
@@ -64,11 +67,12 @@ func appendNumberDataPoints(records []models.MetricRecord, projectId uuid.UUID,
 			value = float64(v.AsInt)
 		}
 		records = append(records, models.MetricRecord{
-			ProjectId:  projectId,
-			Name:       name,
-			Value:      value,
-			RecordedAt: nanoToTime(dp.TimeUnixNano),
-			ServerName: serverName,
+			ProjectId:   projectId,
+			Name:        name,
+			Value:       value,
+			RecordedAt:  nanoToTime(dp.TimeUnixNano),
+			ServerName:  serverName,
+			Environment: environment,
 		})
 	}
 	return records
@@ -4,10 +4,16 @@ import (
 	"backend/app/hooks"
 	"backend/app/middleware"
 	"backend/app/models"
+	"backend/app/pgdb"
 	"backend/app/repositories"
+	"backend/app/services"
+	"database/sql"
+	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	traceway "go.tracewayapp.com"
 )
 
@@ -38,6 +44,7 @@ func (o otelController) ExportTraces(c *gin.Context) {
 	}
 
 	endpoints, tasks, spans, exceptions := convertTraces(projectId, req)
+	resolveSourceMaps(c, projectId, exceptions)
 
 	if len(endpoints) > 0 {
 		if err := repositories.EndpointRepository.InsertAsync(c, endpoints); err != nil {
@@ -77,6 +84,38 @@ func (o otelController) ExportTraces(c *gin.Context) {
 	writeTraceResponse(c)
 }
 
+// resolveSourceMaps symbolicates browser exceptions with the source maps uploaded for their service.version,
+// OTLP exceptions carry no debug IDs. Batches without a JavaScript frame, e.g. of Go or Java services, aren't looked up.
+func resolveSourceMaps(c *gin.Context, projectId uuid.UUID, exceptions []models.ExceptionStackTrace) {
+	hasBrowserFrames := slices.ContainsFunc(exceptions, func(est models.ExceptionStackTrace) bool {
+		return !est.IsMessage && services.HasBrowserFrames(est.StackTrace)
+	})
+	if !hasBrowserFrames {
+		return
+	}
+
+	resolved := services.ResolveExceptions(c, projectId, exceptions, func(version string) []*models.SourceMap {
+		sourceMaps, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
+			return repositories.SourceMapRepository.FindForVersion(tx, projectId, version)
+		})
+		if err != nil {
+			traceway.CaptureException(fmt.Errorf("failed to load source maps of version %s: %w", version, err))
+		}
+		return sourceMaps
+	}, nil)
+
+	// exceptions are resolved before they're stored, the resolved copies take their place
+	byId := make(map[uuid.UUID]models.ExceptionStackTrace, len(resolved))
+	for _, est := range resolved {
+		byId[est.Id] = est
+	}
+	for i, est := range exceptions {
+		if r, ok := byId[est.Id]; ok {
+			exceptions[i] = r
+		}
+	}
+}
+
 func (o otelController) ExportMetrics(c *gin.Context) {
 	projectId, err := middleware.GetProjectId(c)
 	if err != nil {
@@ -1,8 +1,8 @@
 package otelcontrollers
 
 import (
-	"backend/app/controllers/clientcontrollers"
 	"backend/app/models"
+	"backend/app/services"
 	"fmt"
 	"time"
 
@@ -22,6 +22,8 @@ func convertTraces(projectId uuid.UUID, req *coltracepb.ExportTraceServiceReques
 		resourceAttrs := rs.GetResource().GetAttributes()
 		serverName := getStringAttribute(resourceAttrs, "service.name")
 		appVersion := getStringAttribute(resourceAttrs, "service.version")
+		environment := getEnvironmentAttribute(resourceAttrs)
+		resourceAttributes := extractAttributes(resourceAttrs)
 
 		for _, ss := range rs.ScopeSpans {
 			for _, span := range ss.Spans {
@@ -38,23 +40,38 @@ func convertTraces(projectId uuid.UUID, req *coltracepb.ExportTraceServiceReques
 					if span.Kind == tracepb.Span_SPAN_KIND_SERVER && hasHTTPAttributes(spanAttrs) {
 						endpoints = append(endpoints, buildEndpoint(
 							traceId, projectId, span, spanAttrs, allAttrs,
-							startTime, duration, serverName, appVersion,
+							startTime, duration, serverName, appVersion, environment, resourceAttributes,
 						))
 					} else {
 						tasks = append(tasks, buildTask(
 							traceId, projectId, span, allAttrs,
-							startTime, duration, serverName, appVersion,
+							startTime, duration, serverName, appVersion, environment, resourceAttributes,
 						))
 					}
 				} else {
+					parentId := otelSpanIDToUUID(span.ParentSpanId)
+					httpMethod, httpHost, httpRoute, httpStatusCode := getOutgoingHTTPCall(span.Kind, spanAttrs)
 					spans = append(spans, models.Span{
-						Id:         spanId,
-						TraceId:    traceId,
-						ProjectId:  projectId,
-						Name:       span.Name,
-						StartTime:  startTime,
-						Duration:   duration,
-						RecordedAt: startTime,
+						Id:             spanId,
+						TraceId:        traceId,
+						ProjectId:      projectId,
+						Name:           span.Name,
+						StartTime:      startTime,
+						Duration:       duration,
+						RecordedAt:     startTime,
+						Environment:    environment,
+						ParentId:       &parentId,
+						ServiceName:    serverName,
+						Kind:           spanKindName(span.Kind),
+						Attributes:     allAttrs,
+						IsError:        span.GetStatus().GetCode() == tracepb.Status_STATUS_CODE_ERROR,
+						PeerService:    getPeerService(span.Kind, spanAttrs),
+						DbSystem:       getDbSystem(spanAttrs),
+						DbStatement:    getDbStatement(spanAttrs),
+						HttpMethod:     httpMethod,
+						HttpHost:       httpHost,
+						HttpRoute:      httpRoute,
+						HttpStatusCode: httpStatusCode,
 					})
 				}
 
@@ -67,7 +84,7 @@ func convertTraces(projectId uuid.UUID, req *coltracepb.ExportTraceServiceReques
 					if event.Name == "exception" {
 						exc := buildException(
 							projectId, traceId, traceType, event,
-							serverName, appVersion,
+							serverName, appVersion, environment,
 						)
 						exceptions = append(exceptions, exc)
 					}
@@ -95,7 +112,8 @@ func buildEndpoint(
 	allAttrs map[string]string,
 	startTime time.Time,
 	duration time.Duration,
-	serverName, appVersion string,
+	serverName, appVersion, environment string,
+	resourceAttributes map[string]string,
 ) models.Endpoint {
 	endpoint := getHTTPEndpoint(attrs, span.Name)
 
@@ -119,17 +137,19 @@ func buildEndpoint(
 	}
 
 	return models.Endpoint{
-		Id:         id,
-		ProjectId:  projectId,
-		Endpoint:   endpoint,
-		Duration:   duration,
-		RecordedAt: startTime,
-		StatusCode: statusCode,
-		BodySize:   bodySize,
-		ClientIP:   clientIP,
-		Attributes: allAttrs,
-		AppVersion: appVersion,
-		ServerName: serverName,
+		Id:                 id,
+		ProjectId:          projectId,
+		Endpoint:           endpoint,
+		Duration:           duration,
+		RecordedAt:         startTime,
+		StatusCode:         statusCode,
+		BodySize:           bodySize,
+		ClientIP:           clientIP,
+		Attributes:         allAttrs,
+		AppVersion:         appVersion,
+		ServerName:         serverName,
+		Environment:        environment,
+		ResourceAttributes: resourceAttributes,
 	}
 }
 
@@ -155,17 +175,20 @@ func buildTask(
 	allAttrs map[string]string,
 	startTime time.Time,
 	duration time.Duration,
-	serverName, appVersion string,
+	serverName, appVersion, environment string,
+	resourceAttributes map[string]string,
 ) models.Task {
 	return models.Task{
-		Id:         id,
-		ProjectId:  projectId,
-		TaskName:   span.Name,
-		Duration:   duration,
-		RecordedAt: startTime,
-		Attributes: allAttrs,
-		AppVersion: appVersion,
-		ServerName: serverName,
+		Id:                 id,
+		ProjectId:          projectId,
+		TaskName:           span.Name,
+		Duration:           duration,
+		RecordedAt:         startTime,
+		Attributes:         allAttrs,
+		AppVersion:         appVersion,
+		ServerName:         serverName,
+		Environment:        environment,
+		ResourceAttributes: resourceAttributes,
 	}
 }
 
@@ -173,7 +196,7 @@ func buildException(
 	projectId, traceId uuid.UUID,
 	traceType string,
 	event *tracepb.Span_Event,
-	serverName, appVersion string,
+	serverName, appVersion, environment string,
 ) models.ExceptionStackTrace {
 	eventAttrs := event.Attributes
 	excType := getStringAttribute(eventAttrs, "exception.type")
@@ -181,18 +204,31 @@ func buildException(
 	excStacktrace := getStringAttribute(eventAttrs, "exception.stacktrace")
 
 	stackTrace := formatExceptionStackTrace(excType, excMessage, excStacktrace)
-	hash := clientcontrollers.ComputeExceptionHash(stackTrace, false)
+	hash := services.ComputeExceptionHash(stackTrace, false)
+
+	// the event attributes carry the type and message, the frames are parsed from the stack trace
+	parsed := services.ParseStackTrace(stackTrace)
+	if excType == "" {
+		excType = parsed.Type
+	}
+	if excMessage == "" {
+		excMessage = parsed.Message
+	}
 
 	return models.ExceptionStackTrace{
-		Id:            uuid.New(),
-		ProjectId:     projectId,
-		TraceId:       &traceId,
-		TraceType:     traceType,
-		ExceptionHash: hash,
-		StackTrace:    stackTrace,
-		RecordedAt:    nanoToTime(event.TimeUnixNano),
-		AppVersion:    appVersion,
-		ServerName:    serverName,
+		Id:               uuid.New(),
+		ProjectId:        projectId,
+		TraceId:          &traceId,
+		TraceType:        traceType,
+		ExceptionHash:    hash,
+		StackTrace:       stackTrace,
+		RecordedAt:       nanoToTime(event.TimeUnixNano),
+		AppVersion:       appVersion,
+		ServerName:       serverName,
+		Environment:      environment,
+		ExceptionType:    excType,
+		ExceptionMessage: excMessage,
+		Frames:           parsed.Frames,
 	}
 }
 
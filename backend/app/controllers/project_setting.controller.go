@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	traceway "go.tracewayapp.com"
+)
+
+type projectSettingController struct{}
+
+// UpdateProjectSettingsRequest is a partial update, settings missing from the request are unchanged
+type UpdateProjectSettingsRequest struct {
+	ApdexThresholdMs      *int    `json:"apdexThresholdMs" binding:"omitempty,min=1,max=600000"`
+	UserIdentityAttribute *string `json:"userIdentityAttribute" binding:"omitempty,min=1,max=255"`
+	InAppPrefixes         *string `json:"inAppPrefixes" binding:"omitempty,max=2000"`
+}
+
+func (p projectSettingController) GetSettings(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	settings, err := loadProjectSettings(c, projectId)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading project settings: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (p projectSettingController) UpdateSettings(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request UpdateProjectSettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.ProjectSetting, error) {
+		settings, err := repositories.ProjectSettingRepository.FindByProjectOrDefault(tx, projectId)
+		if err != nil {
+			return nil, err
+		}
+		if request.ApdexThresholdMs != nil {
+			settings.ApdexThresholdMs = *request.ApdexThresholdMs
+		}
+		if request.UserIdentityAttribute != nil {
+			settings.UserIdentityAttribute = *request.UserIdentityAttribute
+		}
+		if request.InAppPrefixes != nil {
+			settings.InAppPrefixes = *request.InAppPrefixes
+		}
+		return repositories.ProjectSettingRepository.Save(tx, settings)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error saving project settings: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// loadProjectSettings loads the stored settings of a project, falling back to the defaults
+func loadProjectSettings(c *gin.Context, projectId uuid.UUID) (*models.ProjectSetting, error) {
+	span := traceway.StartSpan(c, "loading project settings")
+	settings, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.ProjectSetting, error) {
+		return repositories.ProjectSettingRepository.FindByProjectOrDefault(tx, projectId)
+	})
+	span.End()
+	return settings, err
+}
+
+var ProjectSettingController = projectSettingController{}
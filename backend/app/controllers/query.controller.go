@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/repositories"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	traceway "go.tracewayapp.com"
+)
+
+// defaultNPlusOneRepetitions is how many times a statement has to run inside one trace to be reported as N+1
+const defaultNPlusOneRepetitions = 10
+
+type queryController struct{}
+
+type QuerySearchRequest struct {
+	FromDate      time.Time        `json:"fromDate"`
+	ToDate        time.Time        `json:"toDate"`
+	OrderBy       string           `json:"orderBy"`
+	SortDirection string           `json:"sortDirection"`
+	Pagination    PaginationParams `json:"pagination"`
+	Search        string           `json:"search"`
+	models.TelemetryFilter
+}
+
+type NPlusOneRequest struct {
+	FromDate       time.Time `json:"fromDate"`
+	ToDate         time.Time `json:"toDate"`
+	MinRepetitions int       `json:"minRepetitions"` // defaults to 10
+	models.TelemetryFilter
+}
+
+func (q queryController) FindQueries(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request QuerySearchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span := traceway.StartSpan(c, "loading queries")
+	stats, total, err := repositories.QueryRepository.FindQueries(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection, request.Search, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading queries: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse[models.QueryStats]{
+		Data: stats,
+		Pagination: Pagination{
+			Page:       request.Pagination.Page,
+			PageSize:   request.Pagination.PageSize,
+			Total:      total,
+			TotalPages: (total + int64(request.Pagination.PageSize) - 1) / int64(request.Pagination.PageSize),
+		},
+	})
+}
+
+// FindNPlusOne lists the statements repeated inside single traces, the usual sign of a query in a loop
+func (q queryController) FindNPlusOne(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request NPlusOneRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.MinRepetitions < 2 {
+		request.MinRepetitions = defaultNPlusOneRepetitions
+	}
+
+	span := traceway.StartSpan(c, "loading n+1 queries")
+	queries, err := repositories.QueryRepository.FindNPlusOne(c, projectId, request.FromDate, request.ToDate, request.MinRepetitions, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading n+1 queries: %w", err))
+		return
+	}
+	if queries == nil {
+		queries = []models.NPlusOneQuery{}
+	}
+
+	c.JSON(http.StatusOK, queries)
+}
+
+var QueryController = queryController{}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"backend/app/services"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	traceway "go.tracewayapp.com"
+)
+
+type releaseController struct{}
+
+type CreateReleaseRequest struct {
+	Version     string     `json:"version" binding:"required,max=255"`
+	Environment string     `json:"environment" binding:"max=255"`
+	CommitSha   string     `json:"commitSha" binding:"max=255"`
+	ReleasedAt  *time.Time `json:"releasedAt"` // defaults to now
+}
+
+// Create registers a release, used from CI with the project token or the source map token
+func (r releaseController) Create(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("UseReleaseAuth middleware must be applied: %w", err))
+		return
+	}
+
+	var request CreateReleaseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	releasedAt := time.Now().UTC()
+	if request.ReleasedAt != nil {
+		releasedAt = request.ReleasedAt.UTC()
+	}
+
+	release, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.Release, error) {
+		return repositories.ReleaseRepository.Upsert(tx, &models.Release{
+			ProjectId:   projectId,
+			Version:     request.Version,
+			Environment: request.Environment,
+			CommitSha:   request.CommitSha,
+			ReleasedAt:  releasedAt,
+		})
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error saving release: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, release)
+}
+
+func (r releaseController) List(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	releases, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.ReleaseWithSourceMaps, error) {
+		return repositories.ReleaseRepository.FindByProject(tx, projectId, 100)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading releases: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, releases)
+}
+
+type ReleaseCompareRequest struct {
+	// BaseVersion and TargetVersion are optional; when both are empty the latest version is compared to the previous one
+	BaseVersion   string    `json:"baseVersion"`
+	TargetVersion string    `json:"targetVersion"`
+	FromDate      time.Time `json:"fromDate"`
+	ToDate        time.Time `json:"toDate"`
+	models.TelemetryFilter
+}
+
+func (r releaseController) Compare(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request ReleaseCompareRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if request.ToDate.IsZero() {
+		request.ToDate = time.Now()
+	}
+	if request.FromDate.IsZero() {
+		request.FromDate = request.ToDate.Add(-30 * 24 * time.Hour)
+	}
+
+	if request.BaseVersion == "" || request.TargetVersion == "" {
+		if request.BaseVersion != "" || request.TargetVersion != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "baseVersion and targetVersion must be provided together"})
+			return
+		}
+
+		// Prefer the release registry, fall back to the versions seen in the data
+		versions, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]string, error) {
+			return repositories.ReleaseRepository.FindLatestVersions(tx, projectId, 2)
+		})
+		if err != nil {
+			c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading releases: %w", err))
+			return
+		}
+		if len(versions) < 2 {
+			span := traceway.StartSpan(c, "loading recent versions")
+			versions, err = repositories.AppVersionRepository.FindRecentVersions(c, projectId, request.FromDate, request.ToDate, 2, request.TelemetryFilter)
+			span.End()
+			if err != nil {
+				c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading recent versions: %w", err))
+				return
+			}
+		}
+		if len(versions) < 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "At least two app versions are needed to compare releases"})
+			return
+		}
+		request.TargetVersion = versions[0]
+		request.BaseVersion = versions[1]
+	}
+	if request.BaseVersion == request.TargetVersion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "baseVersion and targetVersion must differ"})
+		return
+	}
+
+	span := traceway.StartSpan(c, "loading endpoint stats by version")
+	endpointStats, err := repositories.EndpointRepository.GetStatsForVersions(c, projectId, []string{request.BaseVersion, request.TargetVersion}, request.FromDate, request.ToDate, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading endpoint stats by version: %w", err))
+		return
+	}
+
+	aliases, err := loadExceptionGroupAliases(c, projectId)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception group aliases: %w", err))
+		return
+	}
+
+	span = traceway.StartSpan(c, "loading exception counts by version")
+	exceptionCounts, err := repositories.ExceptionStackTraceRepository.CountByHashForVersions(c, projectId, request.BaseVersion, request.TargetVersion, request.FromDate, request.ToDate, aliases, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exception counts by version: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, services.BuildReleaseComparison(request.BaseVersion, request.TargetVersion, endpointStats, exceptionCounts))
+}
+
+// loadDeployMarkers returns the releases deployed within the range for display on charts
+func loadDeployMarkers(c *gin.Context, projectId uuid.UUID, start, end time.Time) ([]models.DeployMarker, error) {
+	span := traceway.StartSpan(c, "loading deploy markers")
+	releases, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.Release, error) {
+		return repositories.ReleaseRepository.FindBetween(tx, projectId, start, end)
+	})
+	span.End()
+	if err != nil {
+		return nil, err
+	}
+
+	markers := make([]models.DeployMarker, 0, len(releases))
+	for _, release := range releases {
+		markers = append(markers, models.DeployMarker{
+			Version:     release.Version,
+			Environment: release.Environment,
+			CommitSha:   release.CommitSha,
+			Timestamp:   release.ReleasedAt,
+		})
+	}
+	return markers, nil
+}
+
+var ReleaseController = releaseController{}
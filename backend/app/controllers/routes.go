@@ -46,6 +46,23 @@ func RegisterControllers(router *gin.RouterGroup) {
 	router.GET("/dashboard", middleware.UseAppAuth, middleware.RequireProjectAccess, DashboardController.GetDashboard)
 	router.GET("/dashboard/overview", middleware.UseAppAuth, middleware.RequireProjectAccess, DashboardController.GetDashboardOverview)
 
+	// Health thresholds (projectId in query param)
+	router.GET("/health-thresholds", middleware.UseAppAuth, middleware.RequireProjectAccess, HealthThresholdController.GetHealthThresholds)
+	router.PUT("/health-thresholds", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, HealthThresholdController.UpdateHealthThresholds)
+	router.DELETE("/health-thresholds/:metricType", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, HealthThresholdController.ResetHealthThreshold)
+
+	// Project settings (projectId in query param)
+	router.GET("/project-settings", middleware.UseAppAuth, middleware.RequireProjectAccess, ProjectSettingController.GetSettings)
+	router.PUT("/project-settings", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, ProjectSettingController.UpdateSettings)
+
+	// SLOs (projectId in query param)
+	router.GET("/slos", middleware.UseAppAuth, middleware.RequireProjectAccess, SloController.ListSlos)
+	router.POST("/slos", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, SloController.CreateSlo)
+	router.GET("/slos/:sloId", middleware.UseAppAuth, middleware.RequireProjectAccess, SloController.GetSlo)
+	router.GET("/slos/:sloId/history", middleware.UseAppAuth, middleware.RequireProjectAccess, SloController.GetSloHistory)
+	router.PUT("/slos/:sloId", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, SloController.UpdateSlo)
+	router.DELETE("/slos/:sloId", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, SloController.DeleteSlo)
+
 	// Metrics endpoints (projectId in query param)
 	router.GET("/metrics/application", middleware.UseAppAuth, middleware.RequireProjectAccess, MetricsController.GetApplicationMetrics)
 	router.GET("/metrics/stats", middleware.UseAppAuth, middleware.RequireProjectAccess, MetricsController.GetStatsMetrics)
@@ -56,6 +73,9 @@ func RegisterControllers(router *gin.RouterGroup) {
 	router.POST("/endpoints/grouped", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointController.FindGroupedByEndpoint)
 	router.POST("/endpoints/endpoint", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointController.FindByEndpoint)
 	router.POST("/endpoints/chart", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointController.GetStackedChart)
+	router.POST("/endpoints/apdex", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointController.GetApdexSeries)
+	router.POST("/endpoints/span-breakdown", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointController.GetSpanBreakdown)
+	router.POST("/endpoints/series", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointController.GetPerformanceSeries)
 	router.GET("/endpoints/slow", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointController.GetSlowEndpoint)
 	router.POST("/endpoints/slow", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, EndpointController.SetSlowEndpoint)
 	router.POST("/endpoints/:endpointId", middleware.UseAppAuth, middleware.RequireProjectAccess, EndpointDetailController.GetEndpointDetail)
@@ -64,15 +84,46 @@ func RegisterControllers(router *gin.RouterGroup) {
 	router.POST("/tasks", middleware.UseAppAuth, middleware.RequireProjectAccess, TaskController.FindAllTasks)
 	router.POST("/tasks/grouped", middleware.UseAppAuth, middleware.RequireProjectAccess, TaskController.FindGroupedByTaskName)
 	router.POST("/tasks/task", middleware.UseAppAuth, middleware.RequireProjectAccess, TaskController.FindByTaskName)
+	router.POST("/tasks/series", middleware.UseAppAuth, middleware.RequireProjectAccess, TaskController.GetPerformanceSeries)
 	router.POST("/tasks/:taskId", middleware.UseAppAuth, middleware.RequireProjectAccess, TaskDetailController.GetTaskDetail)
 
+	// Attribute breakdowns of endpoints, tasks and exceptions (projectId in body)
+	router.POST("/breakdown", middleware.UseAppAuth, middleware.RequireProjectAccess, AttributeBreakdownController.GetBreakdown)
+
+	// Latency distribution of an endpoint or task (projectId in body)
+	router.POST("/latency/histogram", middleware.UseAppAuth, middleware.RequireProjectAccess, LatencyController.GetHistogram)
+	router.POST("/latency/heatmap", middleware.UseAppAuth, middleware.RequireProjectAccess, LatencyController.GetHeatmap)
+
+	// Distributed traces and the service map (projectId in query param)
+	router.POST("/traces/search", middleware.UseAppAuth, middleware.RequireProjectAccess, TraceController.Search)
+	router.GET("/traces/:traceId", middleware.UseAppAuth, middleware.RequireProjectAccess, TraceController.GetTrace)
+	router.GET("/service-map", middleware.UseAppAuth, middleware.RequireProjectAccess, ServiceMapController.GetServiceMap)
+
+	// Database queries (projectId in body)
+	router.POST("/queries", middleware.UseAppAuth, middleware.RequireProjectAccess, QueryController.FindQueries)
+	router.POST("/queries/n-plus-one", middleware.UseAppAuth, middleware.RequireProjectAccess, QueryController.FindNPlusOne)
+
+	// Outbound HTTP dependencies (projectId in body)
+	router.POST("/dependencies", middleware.UseAppAuth, middleware.RequireProjectAccess, DependencyController.FindOutbound)
+	router.POST("/dependencies/series", middleware.UseAppAuth, middleware.RequireProjectAccess, DependencyController.GetSeries)
+
 	// Exceptions (projectId in body)
 	router.POST("/exception-stack-traces", middleware.UseAppAuth, middleware.RequireProjectAccess, ExceptionStackTraceController.FindGrouppedExceptionStackTraces)
 	router.POST("/exception-stack-traces/archive", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, ExceptionStackTraceController.ArchiveExceptions)
 	router.POST("/exception-stack-traces/unarchive", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, ExceptionStackTraceController.UnarchiveExceptions)
+	router.POST("/exception-stack-traces/merge", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, ExceptionStackTraceController.MergeExceptions)
+	router.POST("/exception-stack-traces/unmerge", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, ExceptionStackTraceController.UnmergeExceptions)
 	router.POST("/exception-stack-traces/by-id/:exceptionId", middleware.UseAppAuth, middleware.RequireProjectAccess, ExceptionStackTraceController.FindById)
 	router.POST("/exception-stack-traces/:hash", middleware.UseAppAuth, middleware.RequireProjectAccess, ExceptionStackTraceController.FindByHash)
 
+	// Releases (projectId in query param)
+	router.POST("/releases", middleware.UseReleaseAuth, ReleaseController.Create)
+	router.GET("/releases", middleware.UseAppAuth, middleware.RequireProjectAccess, ReleaseController.List)
+	router.POST("/releases/compare", middleware.UseAppAuth, middleware.RequireProjectAccess, ReleaseController.Compare)
+
+	// Environments (projectId in query param)
+	router.GET("/environments", middleware.UseAppAuth, middleware.RequireProjectAccess, EnvironmentController.List)
+
 	// Auth
 	router.POST("/login", middleware.Transactional, AuthController.Login)
 	router.POST("/register", middleware.Transactional, AuthController.Register)
@@ -90,6 +141,7 @@ func RegisterControllers(router *gin.RouterGroup) {
 	router.GET("/organizations/:organizationId/settings", middleware.UseAppAuth, middleware.RequireAdminAccess, OrganizationController.GetSettings)
 	router.PUT("/organizations/:organizationId/settings", middleware.UseAppAuth, middleware.RequireAdminAccess, middleware.Transactional, OrganizationController.UpdateSettings)
 	router.GET("/organizations/:organizationId/members", middleware.UseAppAuth, middleware.RequireAdminAccess, OrganizationController.GetMembers)
+	router.GET("/organizations/:organizationId/sourcemaps/cache-stats", middleware.UseAppAuth, middleware.RequireAdminAccess, SourceMapController.CacheStats)
 
 	// Member management (admin/owner) - TRANSACTIONAL
 	router.PUT("/organizations/:organizationId/members/:userId", middleware.UseAppAuth, middleware.RequireAdminAccess, middleware.Transactional, MemberController.UpdateRole)
@@ -108,6 +160,11 @@ func RegisterControllers(router *gin.RouterGroup) {
 	// Source map management
 	router.POST("/projects/source-map-token", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, ProjectController.GenerateSourceMapToken)
 	router.POST("/sourcemaps/upload", middleware.UseSourceMapAuth, SourceMapController.Upload)
+	router.GET("/sourcemaps/versions", middleware.UseAppAuth, middleware.RequireProjectAccess, SourceMapController.ListVersions)
+	router.GET("/sourcemaps/versions/:version", middleware.UseAppAuth, middleware.RequireProjectAccess, SourceMapController.ListFiles)
+	router.DELETE("/sourcemaps/versions/:version", middleware.UseAppAuth, middleware.RequireProjectAccess, middleware.RequireWriteAccess, SourceMapController.DeleteVersion)
+	router.GET("/sourcemaps/files/:sourceMapId", middleware.UseAppAuth, middleware.RequireProjectAccess, SourceMapController.Inspect)
+	router.GET("/sourcemaps/files/:sourceMapId/download", middleware.UseAppAuth, middleware.RequireProjectAccess, SourceMapController.Download)
 
 	for _, register := range ExtensionRoutes {
 		register(router)
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"backend/app/services"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	traceway "go.tracewayapp.com"
+)
+
+type serviceMapController struct{}
+
+// GetServiceMap returns the service dependency graph of the project for the time range (defaults to the last 24h).
+// Only edges between projects of the project's organization are part of it.
+func (s serviceMapController) GetServiceMap(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	start, end := parseTimeRange(c, time.Now())
+
+	projects, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.Project, error) {
+		return repositories.ProjectRepository.FindInSameOrganization(tx, projectId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading projects: %w", err))
+		return
+	}
+	projectIds := make([]uuid.UUID, 0, len(projects))
+	for _, p := range projects {
+		projectIds = append(projectIds, p.Id)
+	}
+	if len(projectIds) == 0 {
+		c.JSON(http.StatusOK, services.BuildServiceMap(nil))
+		return
+	}
+
+	span := traceway.StartSpan(c, "loading service edges")
+	edges, err := repositories.ServiceMapRepository.FindEdges(c, projectId, projectIds, start, end)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading service edges: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, services.BuildServiceMap(edges))
+}
+
+var ServiceMapController = serviceMapController{}
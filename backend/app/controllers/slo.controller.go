@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"backend/app/services"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	traceway "go.tracewayapp.com"
+)
+
+type sloController struct{}
+
+type SloRequest struct {
+	Name               string  `json:"name" binding:"required,max=255"`
+	Endpoint           string  `json:"endpoint"`
+	SloType            string  `json:"sloType" binding:"required,oneof=availability latency"`
+	Target             float64 `json:"target" binding:"required,gt=0,lt=100"`
+	LatencyThresholdMs int     `json:"latencyThresholdMs" binding:"min=0"`
+	WindowDays         int     `json:"windowDays" binding:"required,oneof=7 28 30"`
+}
+
+func (s sloController) ListSlos(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	slos, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.Slo, error) {
+		return repositories.SloRepository.FindByProject(tx, projectId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading slos: %w", err))
+		return
+	}
+
+	now := time.Now()
+	statuses := make([]*models.SloStatus, 0, len(slos))
+	for _, slo := range slos {
+		status, err := computeSloStatus(c, projectId, slo, now)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error computing slo %d: %w", slo.Id, err))
+			return
+		}
+		statuses = append(statuses, status)
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+func (s sloController) GetSlo(c *gin.Context) {
+	projectId, slo, ok := s.loadSlo(c)
+	if !ok {
+		return
+	}
+
+	status, err := computeSloStatus(c, projectId, slo, time.Now())
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error computing slo %d: %w", slo.Id, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (s sloController) GetSloHistory(c *gin.Context) {
+	projectId, slo, ok := s.loadSlo(c)
+	if !ok {
+		return
+	}
+
+	end := time.Now()
+	start := end.Add(-slo.Window())
+	intervalMinutes := calculateIntervalMinutes(slo.Window())
+
+	span := traceway.StartSpan(c, "loading slo history")
+	points, err := repositories.EndpointRepository.SloEventsByInterval(c, projectId, slo, start, end, intervalMinutes)
+	span.End()
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading slo history: %w", err))
+		return
+	}
+	if points == nil {
+		points = []models.SloHistoryPoint{}
+	}
+
+	c.JSON(http.StatusOK, services.BuildSloHistory(slo, points))
+}
+
+func (s sloController) CreateSlo(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request SloRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateSloRequest(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slo, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.Slo, error) {
+		slo := &models.Slo{ProjectId: projectId}
+		applySloRequest(slo, request)
+		return repositories.SloRepository.Create(tx, slo)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error creating slo: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, slo)
+}
+
+func (s sloController) UpdateSlo(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	sloId, err := strconv.Atoi(c.Param("sloId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+		return
+	}
+
+	var request SloRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateSloRequest(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slo, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.Slo, error) {
+		slo, err := repositories.SloRepository.FindById(tx, projectId, sloId)
+		if err != nil || slo == nil {
+			return nil, err
+		}
+		applySloRequest(slo, request)
+		return slo, repositories.SloRepository.Update(tx, slo)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error updating slo: %w", err))
+		return
+	}
+	if slo == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, slo)
+}
+
+func (s sloController) DeleteSlo(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	sloId, err := strconv.Atoi(c.Param("sloId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+		return
+	}
+
+	_, err = pgdb.ExecuteTransaction(func(tx *sql.Tx) (bool, error) {
+		return true, repositories.SloRepository.Delete(tx, projectId, sloId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error deleting slo: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SLO deleted"})
+}
+
+// loadSlo resolves the project and the SLO from the request, writing the error response when either is missing
+func (s sloController) loadSlo(c *gin.Context) (uuid.UUID, *models.Slo, bool) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return uuid.Nil, nil, false
+	}
+
+	sloId, err := strconv.Atoi(c.Param("sloId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+		return uuid.Nil, nil, false
+	}
+
+	slo, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.Slo, error) {
+		return repositories.SloRepository.FindById(tx, projectId, sloId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading slo: %w", err))
+		return uuid.Nil, nil, false
+	}
+	if slo == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+		return uuid.Nil, nil, false
+	}
+
+	return projectId, slo, true
+}
+
+func computeSloStatus(c *gin.Context, projectId uuid.UUID, slo *models.Slo, now time.Time) (*models.SloStatus, error) {
+	windows := []time.Duration{slo.Window()}
+	for _, w := range services.SloBurnRateWindows {
+		windows = append(windows, w.Duration)
+	}
+
+	span := traceway.StartSpan(c, "loading slo events")
+	counts, err := repositories.EndpointRepository.CountSloEvents(c, projectId, slo, now, windows)
+	span.End()
+	if err != nil {
+		return nil, err
+	}
+
+	return services.BuildSloStatus(slo, counts[0], counts[1:]), nil
+}
+
+func validateSloRequest(request SloRequest) error {
+	if request.SloType == models.SloTypeLatency && request.LatencyThresholdMs <= 0 {
+		return fmt.Errorf("latencyThresholdMs is required for latency SLOs")
+	}
+	return nil
+}
+
+func applySloRequest(slo *models.Slo, request SloRequest) {
+	slo.Name = request.Name
+	slo.Endpoint = request.Endpoint
+	slo.SloType = request.SloType
+	slo.Target = request.Target
+	slo.LatencyThresholdMs = request.LatencyThresholdMs
+	slo.WindowDays = request.WindowDays
+	if slo.SloType != models.SloTypeLatency {
+		slo.LatencyThresholdMs = 0
+	}
+}
+
+var SloController = sloController{}
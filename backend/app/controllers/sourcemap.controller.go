@@ -1,15 +1,20 @@
 package controllers
 
 import (
+	"backend/app/cache"
+	"backend/app/jobs"
 	"backend/app/middleware"
 	"backend/app/models"
 	"backend/app/pgdb"
 	"backend/app/repositories"
+	"backend/app/services"
 	"backend/app/storage"
 	"database/sql"
 	"fmt"
 	"io"
 	"net/http"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,9 +48,18 @@ func (s sourceMapController) Upload(c *gin.Context) {
 		return
 	}
 
-	uploaded := 0
+	// every map is validated before any is stored so a rejected upload leaves the version unchanged
+	type uploadedSourceMap struct {
+		fileName string
+		data     []byte
+		bundled  *services.BundledSourceMap // extracted when stored, data is empty
+		debugId  string
+	}
+	var sourceMapsToStore []uploadedSourceMap
+	summaries := []models.SourceMapSummary{}
 	for _, fileHeader := range files {
-		if !strings.HasSuffix(fileHeader.Filename, ".map") {
+		isBundle := strings.HasSuffix(fileHeader.Filename, ".zip")
+		if !isBundle && !strings.HasSuffix(fileHeader.Filename, ".map") {
 			continue
 		}
 
@@ -67,30 +81,66 @@ func (s sourceMapController) Upload(c *gin.Context) {
 			return
 		}
 
-		storageKey := fmt.Sprintf("sourcemaps/%s/%s/%s", projectId, version, fileHeader.Filename)
+		if isBundle {
+			bundled, err := services.ReadArtifactBundle(data)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s: %s", fileHeader.Filename, err.Error())})
+				return
+			}
+			for _, sourceMap := range bundled {
+				sourceMapsToStore = append(sourceMapsToStore, uploadedSourceMap{fileName: sourceMap.FileName, bundled: &sourceMap, debugId: sourceMap.Summary.DebugId})
+				summaries = append(summaries, sourceMap.Summary)
+			}
+			continue
+		}
+
+		summary, err := services.InspectSourceMap(fileHeader.Filename, data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
-		if err := storage.Store.Write(c, storageKey, data); err != nil {
+		sourceMapsToStore = append(sourceMapsToStore, uploadedSourceMap{fileName: fileHeader.Filename, data: data, debugId: summary.DebugId})
+		summaries = append(summaries, summary)
+	}
+
+	uploaded := 0
+	var debugIds []string
+	for _, sourceMap := range sourceMapsToStore {
+		storageKey := fmt.Sprintf("sourcemaps/%s/%s/%s", projectId, version, sourceMap.fileName)
+		if sourceMap.bundled != nil {
+			sourceMap.data, err = sourceMap.bundled.ReadData()
+			if err != nil {
+				c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("failed to extract source map %s from bundle: %w", sourceMap.fileName, err))
+				return
+			}
+		}
+
+		if err := storage.Store.Write(c, storageKey, sourceMap.data); err != nil {
 			c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("failed to write source map to storage: %w", err))
 			return
 		}
+		cache.SourceMapCache.Delete(storageKey)
 
 		_, err = pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.SourceMap, error) {
-			existing, err := repositories.SourceMapRepository.FindByProjectVersionAndFileName(tx, projectId, version, fileHeader.Filename)
+			existing, err := repositories.SourceMapRepository.FindByProjectVersionAndFileName(tx, projectId, version, sourceMap.fileName)
 			if err != nil {
 				return nil, err
 			}
 			if existing != nil {
 				existing.StorageKey = storageKey
-				existing.FileSize = fileHeader.Size
+				existing.FileSize = int64(len(sourceMap.data))
+				existing.DebugId = sourceMap.debugId
 				existing.UploadedAt = time.Now().UTC()
 				return existing, repositories.SourceMapRepository.Update(tx, existing)
 			}
 			return repositories.SourceMapRepository.Create(tx, &models.SourceMap{
 				ProjectId:  projectId,
 				Version:    version,
-				FileName:   fileHeader.Filename,
+				FileName:   sourceMap.fileName,
 				StorageKey: storageKey,
-				FileSize:   fileHeader.Size,
+				FileSize:   int64(len(sourceMap.data)),
+				DebugId:    sourceMap.debugId,
 			})
 		})
 		if err != nil {
@@ -99,9 +149,156 @@ func (s sourceMapController) Upload(c *gin.Context) {
 		}
 
 		uploaded++
+		if sourceMap.debugId != "" {
+			debugIds = append(debugIds, sourceMap.debugId)
+		}
+	}
+
+	// exceptions recorded before the upload are resolved again in the background
+	if uploaded > 0 {
+		jobs.EnqueueSourceMapResolution(projectId, version, debugIds)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"uploaded": uploaded, "files": summaries})
+}
+
+func (s sourceMapController) ListVersions(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	versions, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMapVersion, error) {
+		return repositories.SourceMapRepository.FindVersionsByProject(tx, projectId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading source map versions: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+func (s sourceMapController) ListFiles(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	sourceMaps, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
+		return repositories.SourceMapRepository.FindByProjectAndVersion(tx, projectId, c.Param("version"))
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading source maps: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, sourceMaps)
+}
+
+// Inspect reports the minified file a source map covers, its sources and whether their content is embedded
+func (s sourceMapController) Inspect(c *gin.Context) {
+	sourceMap, data, ok := s.loadSourceMap(c)
+	if !ok {
+		return
+	}
+
+	summary, err := services.InspectSourceMap(sourceMap.FileName, data)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sourceMap": sourceMap, "summary": summary})
+}
+
+func (s sourceMapController) Download(c *gin.Context) {
+	sourceMap, data, ok := s.loadSourceMap(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(sourceMap.FileName)))
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// DeleteVersion removes the source maps of a version from storage and their metadata
+func (s sourceMapController) DeleteVersion(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	version := c.Param("version")
+	sourceMaps, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
+		sourceMaps, err := repositories.SourceMapRepository.FindByProjectAndVersion(tx, projectId, version)
+		if err != nil || len(sourceMaps) == 0 {
+			return sourceMaps, err
+		}
+		return sourceMaps, repositories.SourceMapRepository.DeleteByProjectAndVersion(tx, projectId, version)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error deleting source map metadata: %w", err))
+		return
+	}
+	if len(sourceMaps) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No source maps uploaded for this version"})
+		return
+	}
+
+	// the files are only removed once nothing references them, one that can't be removed is left behind unused
+	for _, sourceMap := range sourceMaps {
+		cache.SourceMapCache.Delete(sourceMap.StorageKey)
+		if err := storage.Store.Delete(c, sourceMap.StorageKey); err != nil {
+			traceway.CaptureException(fmt.Errorf("failed to delete source map from storage (key=%s): %w", sourceMap.StorageKey, err))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": len(sourceMaps)})
+}
+
+// CacheStats reports the usage of the parsed source map cache. The cache is shared by every project of the instance,
+// so only organization admins see it.
+func (s sourceMapController) CacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, cache.SourceMapCache.Stats())
+}
+
+// loadSourceMap resolves the source map from the request and reads it from storage, writing the error response when it is missing
+func (s sourceMapController) loadSourceMap(c *gin.Context) (*models.SourceMap, []byte, bool) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return nil, nil, false
+	}
+
+	sourceMapId, err := strconv.Atoi(c.Param("sourceMapId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source map ID"})
+		return nil, nil, false
+	}
+
+	sourceMap, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (*models.SourceMap, error) {
+		return repositories.SourceMapRepository.FindByProjectAndId(tx, projectId, sourceMapId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading source map: %w", err))
+		return nil, nil, false
+	}
+	if sourceMap == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Source map not found"})
+		return nil, nil, false
+	}
+
+	data, err := storage.Store.Read(c, sourceMap.StorageKey)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error reading source map from storage: %w", err))
+		return nil, nil, false
 	}
 
-	c.JSON(http.StatusOK, gin.H{"uploaded": uploaded})
+	return sourceMap, data, true
 }
 
 var SourceMapController = sourceMapController{}
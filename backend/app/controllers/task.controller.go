@@ -20,6 +20,7 @@ type TaskSearchRequest struct {
 	OrderBy       string           `json:"orderBy"`
 	SortDirection string           `json:"sortDirection"`
 	Pagination    PaginationParams `json:"pagination"`
+	models.TelemetryFilter
 }
 
 type TaskInstancesRequest struct {
@@ -28,6 +29,7 @@ type TaskInstancesRequest struct {
 	OrderBy       string           `json:"orderBy"`
 	SortDirection string           `json:"sortDirection"`
 	Pagination    PaginationParams `json:"pagination"`
+	models.TelemetryFilter
 }
 
 type TaskInstancesResponse struct {
@@ -36,6 +38,13 @@ type TaskInstancesResponse struct {
 	Pagination Pagination              `json:"pagination"`
 }
 
+type TaskSeriesRequest struct {
+	FromDate time.Time `json:"fromDate"`
+	ToDate   time.Time `json:"toDate"`
+	TaskName string    `json:"taskName" binding:"required"`
+	models.TelemetryFilter
+}
+
 func (e taskController) FindAllTasks(c *gin.Context) {
 	projectId, err := middleware.GetProjectId(c)
 	if err != nil {
@@ -50,7 +59,7 @@ func (e taskController) FindAllTasks(c *gin.Context) {
 	}
 
 	span := traceway.StartSpan(c, "loading tasks")
-	tasks, total, err := repositories.TaskRepository.FindAll(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy)
+	tasks, total, err := repositories.TaskRepository.FindAll(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading tasks: %w", err))
@@ -82,7 +91,7 @@ func (e taskController) FindGroupedByTaskName(c *gin.Context) {
 	}
 
 	span := traceway.StartSpan(c, "loading grouped tasks")
-	stats, total, err := repositories.TaskRepository.FindGroupedByTaskName(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection)
+	stats, total, err := repositories.TaskRepository.FindGroupedByTaskName(c, projectId, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading stats by name: %w", err))
@@ -126,7 +135,7 @@ func (e taskController) FindByTaskName(c *gin.Context) {
 	}
 
 	span := traceway.StartSpan(c, "loading task instances")
-	tasks, total, err := repositories.TaskRepository.FindByTaskName(c, projectId, taskName, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection)
+	tasks, total, err := repositories.TaskRepository.FindByTaskName(c, projectId, taskName, request.FromDate, request.ToDate, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading tasks by name: %w", err))
@@ -135,7 +144,7 @@ func (e taskController) FindByTaskName(c *gin.Context) {
 
 	// Get aggregate stats for this task
 	span = traceway.StartSpan(c, "loading task stats")
-	stats, err := repositories.TaskRepository.GetTaskStats(c, projectId, taskName, request.FromDate, request.ToDate)
+	stats, err := repositories.TaskRepository.GetTaskStats(c, projectId, taskName, request.FromDate, request.ToDate, request.TelemetryFilter)
 	span.End()
 	if err != nil {
 		// Don't fail the request if stats fail, just return nil stats
@@ -154,4 +163,44 @@ func (e taskController) FindByTaskName(c *gin.Context) {
 	})
 }
 
+// GetPerformanceSeries returns the p50/p95/p99, throughput and error rate trend of a single task
+func (e taskController) GetPerformanceSeries(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request TaskSeriesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	intervalMinutes := calculateIntervalMinutes(request.ToDate.Sub(request.FromDate))
+
+	span := traceway.StartSpan(c, "loading task performance series")
+	series, err := repositories.TaskRepository.PerformanceByInterval(c, projectId, request.TaskName, request.FromDate, request.ToDate, intervalMinutes, request.TelemetryFilter)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading task performance series: %w", err))
+		return
+	}
+	if series == nil {
+		series = []models.PerformanceSeriesPoint{}
+	}
+
+	deployMarkers, err := loadDeployMarkers(c, projectId, request.FromDate, request.ToDate)
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading deploy markers: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, PerformanceSeriesResponse{
+		IntervalMinutes: intervalMinutes,
+		Series:          series,
+		DeployMarkers:   deployMarkers,
+	})
+}
+
 var TaskController = taskController{}
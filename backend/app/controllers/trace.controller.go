@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"backend/app/middleware"
+	"backend/app/models"
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"backend/app/services"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	traceway "go.tracewayapp.com"
+)
+
+type traceController struct{}
+
+type TraceSearchRequest struct {
+	FromDate      time.Time                `json:"fromDate"`
+	ToDate        time.Time                `json:"toDate"`
+	Type          string                   `json:"type" binding:"omitempty,oneof=endpoint task"` // defaults to endpoint
+	Name          string                   `json:"name"`                                         // endpoint or task name
+	StatusCodes   []string                 `json:"statusCodes"`                                  // 404, 5xx or 400-499, endpoints only
+	MinDurationMs float64                  `json:"minDurationMs"`
+	MaxDurationMs float64                  `json:"maxDurationMs"`
+	ServerName    string                   `json:"serverName"`
+	AppVersion    string                   `json:"appVersion"`
+	ClientIP      string                   `json:"clientIP"`
+	Attributes    []models.AttributeFilter `json:"attributes"`
+	OrderBy       string                   `json:"orderBy"`
+	SortDirection string                   `json:"sortDirection"`
+	Pagination    PaginationParams         `json:"pagination"`
+	models.TelemetryFilter
+}
+
+// GetTrace assembles a distributed trace from every project of the organization the requested project belongs to.
+// The trace id is accepted as a UUID or as the 32 character hex id used by OTLP.
+func (t traceController) GetTrace(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	traceId, err := uuid.Parse(c.Param("traceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid traceId"})
+		return
+	}
+
+	projects, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.Project, error) {
+		return repositories.ProjectRepository.FindInSameOrganization(tx, projectId)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("error loading projects: %w", err))
+		return
+	}
+
+	projectIds := make([]uuid.UUID, 0, len(projects))
+	projectNames := make(map[uuid.UUID]string, len(projects))
+	for _, p := range projects {
+		projectIds = append(projectIds, p.Id)
+		projectNames[p.Id] = p.Name
+	}
+
+	span := traceway.StartSpan(c, "loading trace endpoints")
+	endpoints, err := repositories.EndpointRepository.FindByTraceIdInProjects(c, projectIds, traceId)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading endpoints: %w", err))
+		return
+	}
+
+	span = traceway.StartSpan(c, "loading trace tasks")
+	tasks, err := repositories.TaskRepository.FindByTraceIdInProjects(c, projectIds, traceId)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading tasks: %w", err))
+		return
+	}
+
+	span = traceway.StartSpan(c, "loading trace spans")
+	spans, err := repositories.SpanRepository.FindByTraceIdInProjects(c, projectIds, traceId)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading spans: %w", err))
+		return
+	}
+
+	span = traceway.StartSpan(c, "loading trace exceptions")
+	exceptions, err := repositories.ExceptionStackTraceRepository.FindAllByTraceIdInProjects(c, projectIds, traceId)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error loading exceptions: %w", err))
+		return
+	}
+
+	if len(endpoints) == 0 && len(tasks) == 0 && len(spans) == 0 && len(exceptions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trace not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, services.BuildTraceWaterfall(traceId, endpoints, tasks, spans, exceptions, projectNames))
+}
+
+// Search finds endpoint or task traces by structured filters, including attribute conditions
+func (t traceController) Search(c *gin.Context) {
+	projectId, err := middleware.GetProjectId(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, traceway.NewStackTraceErrorf("RequireProjectAccess middleware must be applied: %w", err))
+		return
+	}
+
+	var request TraceSearchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := models.TraceSearchQuery{
+		Name:            request.Name,
+		MinDuration:     time.Duration(request.MinDurationMs * float64(time.Millisecond)),
+		MaxDuration:     time.Duration(request.MaxDurationMs * float64(time.Millisecond)),
+		ServerName:      request.ServerName,
+		AppVersion:      request.AppVersion,
+		ClientIP:        request.ClientIP,
+		Attributes:      request.Attributes,
+		TelemetryFilter: request.TelemetryFilter,
+	}
+	for _, value := range request.StatusCodes {
+		statusCodes, err := services.ParseStatusCodeRange(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query.StatusCodes = append(query.StatusCodes, statusCodes)
+	}
+	for _, f := range request.Attributes {
+		if err := services.ValidateAttributeFilter(f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	pagination := func(total int64) Pagination {
+		return Pagination{
+			Page:       request.Pagination.Page,
+			PageSize:   request.Pagination.PageSize,
+			Total:      total,
+			TotalPages: (total + int64(request.Pagination.PageSize) - 1) / int64(request.Pagination.PageSize),
+		}
+	}
+
+	if request.Type == models.TraceEntryTypeTask {
+		if len(query.StatusCodes) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tasks have no status code"})
+			return
+		}
+
+		span := traceway.StartSpan(c, "searching tasks")
+		tasks, total, err := repositories.TaskRepository.Search(c, projectId, request.FromDate, request.ToDate, query, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection)
+		span.End()
+		if err != nil {
+			c.AbortWithError(500, traceway.NewStackTraceErrorf("error searching tasks: %w", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, PaginatedResponse[models.Task]{Data: tasks, Pagination: pagination(total)})
+		return
+	}
+
+	span := traceway.StartSpan(c, "searching endpoints")
+	endpoints, total, err := repositories.EndpointRepository.Search(c, projectId, request.FromDate, request.ToDate, query, request.Pagination.Page, request.Pagination.PageSize, request.OrderBy, request.SortDirection)
+	span.End()
+	if err != nil {
+		c.AbortWithError(500, traceway.NewStackTraceErrorf("error searching endpoints: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse[models.Endpoint]{Data: endpoints, Pagination: pagination(total)})
+}
+
+var TraceController = traceController{}
@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"backend/app/services"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+const (
+	// serviceMapLag leaves late spans time to arrive before their bucket is aggregated
+	serviceMapLag = 2 * time.Minute
+	// serviceMapLateWindow is aggregated again on every run to pick up the spans buffered SDKs send late,
+	// spans arriving later than that are left out of the service map
+	serviceMapLateWindow = 15 * time.Minute
+	// serviceMapLockKey is the Postgres advisory lock held while aggregating so instances don't run at the same time
+	serviceMapLockKey int64 = 0x5e41ce
+)
+
+// StartServiceMapAggregation periodically rolls the new spans up into service_edges.
+// Every instance runs the job, the advisory lock lets one of them aggregate at a time.
+func StartServiceMapAggregation(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		var next time.Time
+		for {
+			next = aggregateServiceMap(ctx, next)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// aggregateServiceMap aggregates every complete bucket starting at next, along with the late window, and returns the
+// first bucket still to do. Buckets are replaced when aggregated again.
+func aggregateServiceMap(ctx context.Context, next time.Time) time.Time {
+	// the transaction holds the lock for the whole run, it's released on commit
+	result, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) (time.Time, error) {
+		locked, err := pgdb.TryAdvisoryXactLock(tx, serviceMapLockKey)
+		if err != nil || !locked {
+			return next, err
+		}
+		return aggregateServiceMapBuckets(ctx, tx, next), nil
+	})
+	if err != nil {
+		log.Printf("service map: error taking the aggregation lock: %v", err)
+		return next
+	}
+	return result
+}
+
+func aggregateServiceMapBuckets(ctx context.Context, tx *sql.Tx, next time.Time) time.Time {
+	if next.IsZero() {
+		latest, err := repositories.ServiceMapRepository.FindLatestBucket(ctx)
+		if err != nil {
+			log.Printf("service map: error loading the latest bucket: %v", err)
+			return next
+		}
+		if latest.IsZero() {
+			next = time.Now().Add(-time.Hour).Truncate(repositories.ServiceMapBucket)
+		} else {
+			next = latest.Add(repositories.ServiceMapBucket)
+		}
+	}
+
+	until := time.Now().Add(-serviceMapLag).Truncate(repositories.ServiceMapBucket)
+	start := next
+	if lateStart := until.Add(-serviceMapLateWindow); lateStart.Before(start) {
+		start = lateStart
+	}
+	if !until.After(start) {
+		return next
+	}
+
+	projects, err := repositories.ProjectRepository.FindAll(tx)
+	if err != nil {
+		log.Printf("service map: error loading projects: %v", err)
+		return next
+	}
+	if len(projects) == 0 {
+		return until
+	}
+
+	if err := repositories.ServiceMapRepository.AggregateEdges(ctx, start, until, services.ServiceMapScopes(projects)); err != nil {
+		log.Printf("service map: error aggregating edges: %v", err)
+		return next
+	}
+	return until
+}
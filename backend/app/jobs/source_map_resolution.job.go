@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"backend/app/models"
+	"backend/app/pgdb"
+	"backend/app/repositories"
+	"backend/app/services"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// sourceMapResolutionWindow is how far back exceptions are resolved again after a source map upload
+	sourceMapResolutionWindow = 24 * time.Hour
+	// sourceMapResolutionLimit caps the exceptions rewritten per upload, the most recent ones are resolved first
+	sourceMapResolutionLimit = 5000
+)
+
+type sourceMapResolution struct {
+	projectId uuid.UUID
+	version   string
+	debugIds  []string
+}
+
+var sourceMapResolutions = make(chan sourceMapResolution, 100)
+
+// StartSourceMapResolution resolves the exceptions recorded before the source maps of their version were uploaded
+func StartSourceMapResolution(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case resolution := <-sourceMapResolutions:
+				resolveSourceMapVersion(ctx, resolution)
+			}
+		}
+	}()
+}
+
+// EnqueueSourceMapResolution schedules resolving the recent exceptions of a version, and those of any version thrown in
+// scripts built with the debug IDs, again. The request is dropped when the queue is full, uploading the source maps again retries it.
+func EnqueueSourceMapResolution(projectId uuid.UUID, version string, debugIds []string) {
+	select {
+	case sourceMapResolutions <- sourceMapResolution{projectId: projectId, version: version, debugIds: debugIds}:
+	default:
+		log.Printf("source map resolution: queue full, skipping project %s version %s", projectId, version)
+	}
+}
+
+func resolveSourceMapVersion(ctx context.Context, resolution sourceMapResolution) {
+	exceptions, err := repositories.ExceptionStackTraceRepository.FindRecentByVersion(ctx, resolution.projectId, resolution.version, time.Now().Add(-sourceMapResolutionWindow), sourceMapResolutionLimit)
+	if err != nil {
+		log.Printf("source map resolution: error loading exceptions of project %s version %s: %v", resolution.projectId, resolution.version, err)
+		return
+	}
+	if len(resolution.debugIds) > 0 {
+		byDebugId, err := repositories.ExceptionStackTraceRepository.FindRecentByDebugIds(ctx, resolution.projectId, resolution.debugIds, time.Now().Add(-sourceMapResolutionWindow), sourceMapResolutionLimit)
+		if err != nil {
+			log.Printf("source map resolution: error loading exceptions of project %s by debug ID: %v", resolution.projectId, err)
+			return
+		}
+		exceptions = appendMissingExceptions(exceptions, byDebugId)
+	}
+
+	resolved := services.ResolveExceptions(ctx, resolution.projectId, exceptions, func(version string) []*models.SourceMap {
+		sourceMaps, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
+			return repositories.SourceMapRepository.FindByProjectAndVersion(tx, resolution.projectId, version)
+		})
+		if err != nil {
+			log.Printf("source map resolution: error loading source maps of project %s version %s: %v", resolution.projectId, version, err)
+		}
+		return sourceMaps
+	}, func(debugIds []string) []*models.SourceMap {
+		sourceMaps, err := pgdb.ExecuteTransaction(func(tx *sql.Tx) ([]*models.SourceMap, error) {
+			return repositories.SourceMapRepository.FindByDebugIds(tx, resolution.projectId, debugIds)
+		})
+		if err != nil {
+			log.Printf("source map resolution: error loading source maps of project %s by debug ID: %v", resolution.projectId, err)
+		}
+		return sourceMaps
+	})
+
+	if len(resolved) == 0 {
+		return
+	}
+	byId := make(map[uuid.UUID]models.ExceptionStackTrace, len(exceptions))
+	for _, est := range exceptions {
+		byId[est.Id] = est
+	}
+	previous := make([]models.ExceptionStackTrace, 0, len(resolved))
+	for _, est := range resolved {
+		previous = append(previous, byId[est.Id])
+	}
+
+	if err := repositories.ExceptionStackTraceRepository.Replace(ctx, resolution.projectId, previous, resolved); err != nil {
+		log.Printf("source map resolution: error saving %d resolved exceptions of project %s: %v", len(resolved), resolution.projectId, err)
+	}
+}
+
+// appendMissingExceptions adds the exceptions not loaded yet, an exception of the uploaded version can also match by debug ID
+func appendMissingExceptions(exceptions, more []models.ExceptionStackTrace) []models.ExceptionStackTrace {
+	loaded := make(map[uuid.UUID]bool, len(exceptions))
+	for _, est := range exceptions {
+		loaded[est.Id] = true
+	}
+	for _, est := range more {
+		if !loaded[est.Id] {
+			exceptions = append(exceptions, est)
+		}
+	}
+	return exceptions
+}
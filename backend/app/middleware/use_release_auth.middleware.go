@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"backend/app/cache"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UseReleaseAuth accepts either the project token or the source map token so releases can be registered from CI
+var UseReleaseAuth func(c *gin.Context)
+
+func InitUseReleaseAuth() {
+	UseReleaseAuth = func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+
+		project := cache.ProjectCache.GetByToken(token)
+		if project == nil {
+			project = cache.ProjectCache.GetBySourceMapToken(token)
+		}
+		if project == nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		c.Set(ProjectContextKey, project)
+		c.Set(ProjectIdContextKey, project.Id)
+
+		c.Next()
+	}
+}
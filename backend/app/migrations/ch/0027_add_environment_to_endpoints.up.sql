@@ -0,0 +1 @@
+ALTER TABLE endpoints ADD COLUMN environment LowCardinality(String) DEFAULT ''
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN environment LowCardinality(String) DEFAULT ''
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE metric_records ADD COLUMN environment LowCardinality(String) DEFAULT ''
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN resource_attributes Map(LowCardinality(String), String)
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN parent_id Nullable(UUID)
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN service_name LowCardinality(String) DEFAULT ''
\ No newline at end of file
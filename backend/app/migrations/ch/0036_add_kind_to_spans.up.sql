@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN kind LowCardinality(String) DEFAULT ''
\ No newline at end of file
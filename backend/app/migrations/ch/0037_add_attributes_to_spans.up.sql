@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN attributes String DEFAULT '{}'
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN is_error UInt8 DEFAULT 0
\ No newline at end of file
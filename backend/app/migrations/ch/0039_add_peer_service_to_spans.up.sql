@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN peer_service LowCardinality(String) DEFAULT ''
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS service_edges
+(
+    `project_id` UUID,
+    `target_project_id` UUID,
+    `bucket` DateTime,
+    `source` LowCardinality(String),
+    `target` LowCardinality(String),
+    `target_type` LowCardinality(String),
+    `calls` SimpleAggregateFunction(sum, UInt64),
+    `errors` SimpleAggregateFunction(sum, UInt64),
+    `duration_p95` AggregateFunction(quantile(0.95), Int64)
+)
+ENGINE = AggregatingMergeTree
+PARTITION BY toYYYYMM(bucket)
+ORDER BY (project_id, bucket, source, target, target_type, target_project_id)
+SETTINGS index_granularity = 8192
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN db_statement String DEFAULT ''
\ No newline at end of file
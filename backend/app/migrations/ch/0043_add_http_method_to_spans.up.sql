@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN http_method LowCardinality(String) DEFAULT ''
\ No newline at end of file
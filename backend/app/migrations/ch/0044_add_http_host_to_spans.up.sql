@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN http_host LowCardinality(String) DEFAULT ''
\ No newline at end of file
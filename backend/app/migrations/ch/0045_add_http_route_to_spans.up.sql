@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN http_route String DEFAULT ''
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE spans ADD COLUMN http_status_code Int16 DEFAULT 0
\ No newline at end of file
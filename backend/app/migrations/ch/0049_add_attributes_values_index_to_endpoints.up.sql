@@ -0,0 +1 @@
+ALTER TABLE endpoints ADD INDEX idx_attributes_values mapValues(attributes_map) TYPE bloom_filter(0.01) GRANULARITY 1
\ No newline at end of file
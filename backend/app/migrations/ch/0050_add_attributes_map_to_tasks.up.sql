@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN attributes_map Map(String, String) MATERIALIZED CAST(JSONExtractKeysAndValues(attributes, 'String'), 'Map(String, String)')
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE exception_stack_traces ADD COLUMN exception_type LowCardinality(String) DEFAULT ''
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE exception_stack_traces ADD COLUMN raw_stack_trace String DEFAULT ''
\ No newline at end of file
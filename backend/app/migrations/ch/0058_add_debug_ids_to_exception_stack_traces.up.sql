@@ -0,0 +1 @@
+ALTER TABLE exception_stack_traces ADD COLUMN debug_ids Map(String, String)
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS health_thresholds (
+    id SERIAL PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id),
+    metric_type VARCHAR(50) NOT NULL,
+    warning DOUBLE PRECISION NOT NULL,
+    critical DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(project_id, metric_type)
+)
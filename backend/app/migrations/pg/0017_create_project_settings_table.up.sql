@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS project_settings (
+    id SERIAL PRIMARY KEY,
+    project_id UUID NOT NULL UNIQUE REFERENCES projects(id),
+    apdex_threshold_ms INT NOT NULL DEFAULT 500,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+)
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS slos (
+    id SERIAL PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id),
+    name VARCHAR(255) NOT NULL,
+    endpoint VARCHAR(1000) NOT NULL DEFAULT '',
+    slo_type VARCHAR(20) NOT NULL CHECK (slo_type IN ('availability','latency')),
+    target DOUBLE PRECISION NOT NULL,
+    latency_threshold_ms INT NOT NULL DEFAULT 0,
+    window_days INT NOT NULL CHECK (window_days IN (7,28,30)),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+)
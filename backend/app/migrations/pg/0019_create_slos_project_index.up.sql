@@ -0,0 +1 @@
+CREATE INDEX idx_slos_project ON slos(project_id)
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS releases (
+    id SERIAL PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id),
+    version VARCHAR(255) NOT NULL,
+    environment VARCHAR(255) NOT NULL DEFAULT '',
+    commit_sha VARCHAR(255) NOT NULL DEFAULT '',
+    released_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(project_id, version, environment)
+)
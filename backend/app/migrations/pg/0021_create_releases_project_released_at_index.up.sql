@@ -0,0 +1 @@
+CREATE INDEX idx_releases_project_released_at ON releases(project_id, released_at)
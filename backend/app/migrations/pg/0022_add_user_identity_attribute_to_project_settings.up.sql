@@ -0,0 +1 @@
+ALTER TABLE project_settings ADD COLUMN user_identity_attribute VARCHAR(255) NOT NULL DEFAULT 'user.id'
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS exception_group_aliases (
+    id SERIAL PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id),
+    exception_hash VARCHAR(255) NOT NULL,
+    canonical_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(project_id, exception_hash)
+)
@@ -0,0 +1 @@
+ALTER TABLE project_settings ADD COLUMN in_app_prefixes TEXT NOT NULL DEFAULT ''
\ No newline at end of file
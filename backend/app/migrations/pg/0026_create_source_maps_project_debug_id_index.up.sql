@@ -0,0 +1 @@
+CREATE INDEX idx_source_maps_project_debug_id ON source_maps(project_id, debug_id)
@@ -0,0 +1,27 @@
+package models
+
+const (
+	BreakdownSourceEndpoint  = "endpoint"
+	BreakdownSourceTask      = "task"
+	BreakdownSourceException = "exception"
+)
+
+// AttributeBreakdownRow holds the stats of the traces or exception occurrences sharing one attribute value
+type AttributeBreakdownRow struct {
+	Value       string  `json:"value"`
+	Count       uint64  `json:"count"`
+	Share       float64 `json:"share"`       // percentage of the occurrences that have the attribute
+	ErrorRate   float64 `json:"errorRate"`   // percentage, 5xx for endpoints and runs with an exception for tasks
+	P50Duration float64 `json:"p50Duration"` // in ms, endpoints and tasks only
+	P95Duration float64 `json:"p95Duration"` // in ms
+	P99Duration float64 `json:"p99Duration"` // in ms
+	Issues      uint64  `json:"issues"`      // distinct exception groups, exceptions only
+}
+
+// AttributeBreakdown groups endpoints, tasks or exception occurrences by the value of one attribute
+type AttributeBreakdown struct {
+	Key    string                  `json:"key"`
+	Source string                  `json:"source"` // endpoint, task or exception
+	Total  uint64                  `json:"total"`  // occurrences that have the attribute
+	Values []AttributeBreakdownRow `json:"values"` // top values by count
+}
@@ -16,6 +16,8 @@ type ClientExceptionStackTrace struct {
 	Attributes         map[string]string `json:"attributes"`
 	IsMessage          bool              `json:"isMessage"`
 	SessionRecordingId *string           `json:"sessionRecordingId"`
+	// DebugIds maps the script URLs in the stack trace to the debug IDs injected into them at build time
+	DebugIds           map[string]string `json:"debugIds"`
 }
 
 func (c *ClientExceptionStackTrace) ToExceptionStackTrace(exceptionHash, appVersion, serverName string) models.ExceptionStackTrace {
@@ -38,6 +40,7 @@ func (c *ClientExceptionStackTrace) ToExceptionStackTrace(exceptionHash, appVers
 		StackTrace:    c.StackTrace,
 		RecordedAt:    c.RecordedAt,
 		Attributes:    c.Attributes,
+		DebugIds:      c.DebugIds,
 		IsMessage:     c.IsMessage,
 		AppVersion:    appVersion,
 		ServerName:    serverName,
@@ -110,6 +113,7 @@ func (c *ClientTrace) ToTask(appVersion, serverName string) models.Task {
 
 type ClientSpan struct {
 	Id        string        `json:"id"`
+	ParentId  string        `json:"parentId"` // optional, spans without one are nested by time on ingest
 	Name      string        `json:"name"`
 	StartTime time.Time     `json:"startTime"`
 	Duration  time.Duration `json:"duration"`
@@ -124,7 +128,7 @@ func (c *ClientSpan) ParsedId() uuid.UUID {
 }
 
 func (c *ClientSpan) ToSpan(traceId uuid.UUID) models.Span {
-	return models.Span{
+	span := models.Span{
 		Id:      c.ParsedId(),
 		TraceId: traceId,
 		Name:          c.Name,
@@ -132,6 +136,10 @@ func (c *ClientSpan) ToSpan(traceId uuid.UUID) models.Span {
 		Duration:      c.Duration,
 		RecordedAt:    time.Now(),
 	}
+	if parentId, err := uuid.Parse(c.ParentId); err == nil {
+		span.ParentId = &parentId
+	}
+	return span
 }
 
 type ClientSessionRecording struct {
@@ -29,6 +29,7 @@ type DashboardResponse struct {
 	Metrics          []DashboardMetric `json:"metrics"`
 	AvailableServers []string          `json:"availableServers"`
 	LastUpdated      time.Time         `json:"lastUpdated"`
+	DeployMarkers    []DeployMarker    `json:"deployMarkers"`
 }
 
 // TimeSeriesPoint is used internally for querying time-bucketed data
@@ -36,3 +37,14 @@ type TimeSeriesPoint struct {
 	Timestamp time.Time
 	Value     float64
 }
+
+// PerformanceSeriesPoint holds the latency percentiles, throughput and error rate of one interval
+type PerformanceSeriesPoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Count       uint64    `json:"count"`
+	Throughput  float64   `json:"throughput"`  // per minute
+	P50Duration float64   `json:"p50Duration"` // in ms
+	P95Duration float64   `json:"p95Duration"` // in ms
+	P99Duration float64   `json:"p99Duration"` // in ms
+	ErrorRate   float64   `json:"errorRate"`   // percentage
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// OutboundDependency aggregates the outgoing HTTP calls to one host and route template
+type OutboundDependency struct {
+	Host        string        `json:"host"`
+	Method      string        `json:"method"`
+	Route       string        `json:"route"`
+	Calls       uint64        `json:"calls"`
+	Errors      uint64        `json:"errors"`    // failed spans and 5xx responses
+	ErrorRate   float64       `json:"errorRate"` // percentage
+	P50Duration time.Duration `json:"p50Duration"`
+	P95Duration time.Duration `json:"p95Duration"`
+	P99Duration time.Duration `json:"p99Duration"`
+	LastSeen    time.Time     `json:"lastSeen"`
+	Callers     []string      `json:"callers"` // endpoints and tasks that made the call
+}
+
+type DependencyTimeSeriesPoint struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Calls         uint64    `json:"calls"`
+	ErrorRate     float64   `json:"errorRate"`
+	P95DurationMs float64   `json:"p95DurationMs"`
+}
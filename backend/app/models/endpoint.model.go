@@ -10,39 +10,62 @@ type Endpoint struct {
 	Id        uuid.UUID `json:"id" ch:"id"`
 	ProjectId uuid.UUID `json:"projectId" ch:"project_id"`
 	// endpoint is the route from the router/does not contain actual params so it's safe to group on it
-	Endpoint   string            `json:"endpoint" ch:"endpoint"`
-	Duration   time.Duration     `json:"duration" ch:"duration"`
-	RecordedAt time.Time         `json:"recordedAt" ch:"recorded_at"`
-	StatusCode int16             `json:"statusCode" ch:"status_code"`
-	BodySize   int32             `json:"bodySize" ch:"body_size"`
-	ClientIP   string            `json:"clientIP" ch:"client_ip"`
-	Attributes map[string]string `json:"attributes" ch:"attributes"`
-	AppVersion string            `json:"appVersion" ch:"app_version"`
-	ServerName string            `json:"serverName" ch:"server_name"`
+	Endpoint    string            `json:"endpoint" ch:"endpoint"`
+	Duration    time.Duration     `json:"duration" ch:"duration"`
+	RecordedAt  time.Time         `json:"recordedAt" ch:"recorded_at"`
+	StatusCode  int16             `json:"statusCode" ch:"status_code"`
+	BodySize    int32             `json:"bodySize" ch:"body_size"`
+	ClientIP    string            `json:"clientIP" ch:"client_ip"`
+	Attributes  map[string]string `json:"attributes" ch:"attributes"`
+	AppVersion  string            `json:"appVersion" ch:"app_version"`
+	ServerName  string            `json:"serverName" ch:"server_name"`
+	Environment string            `json:"environment" ch:"environment"`
+	// ResourceAttributes are the OTLP resource attributes (host.name, container.id, k8s.pod.name, ...)
+	ResourceAttributes map[string]string `json:"resourceAttributes" ch:"resource_attributes"`
 }
 
 type EndpointStats struct {
-	Endpoint    string        `json:"endpoint"`
-	Count       uint64        `json:"count"`
-	P50Duration time.Duration `json:"p50Duration"`
-	P95Duration time.Duration `json:"p95Duration"`
-	P99Duration time.Duration `json:"p99Duration"`
-	AvgDuration time.Duration `json:"avgDuration"`
-	LastSeen    time.Time     `json:"lastSeen"`
-	Impact       float64       `json:"impact"`       // 0-1 impact score
-	ImpactReason string        `json:"impactReason"` // human-readable explanation of the dominant impact factor
+	Endpoint      string        `json:"endpoint"`
+	Count         uint64        `json:"count"`
+	P50Duration   time.Duration `json:"p50Duration"`
+	P95Duration   time.Duration `json:"p95Duration"`
+	P99Duration   time.Duration `json:"p99Duration"`
+	AvgDuration   time.Duration `json:"avgDuration"`
+	LastSeen      time.Time     `json:"lastSeen"`
+	Apdex         float64       `json:"apdex"`         // 0-1 score against the project's Apdex T
+	Impact        float64       `json:"impact"`        // 0-1 impact score
+	ImpactReason  string        `json:"impactReason"`  // human-readable explanation of the dominant impact factor
+	AffectedUsers uint64        `json:"affectedUsers"` // approximate distinct users
 }
 
 // EndpointDetailStats contains detailed statistics for a specific endpoint
 type EndpointDetailStats struct {
-	Count          int64   `json:"count"`
-	AvgDuration    float64 `json:"avgDuration"`    // in ms
-	MedianDuration float64 `json:"medianDuration"` // in ms
-	P95Duration    float64 `json:"p95Duration"`    // in ms
-	P99Duration    float64 `json:"p99Duration"`    // in ms
-	Apdex          float64 `json:"apdex"`          // 0-1 score
-	ErrorRate      float64 `json:"errorRate"`      // percentage
-	Throughput     float64 `json:"throughput"`     // requests per minute
+	Count            int64   `json:"count"`
+	AvgDuration      float64 `json:"avgDuration"`      // in ms
+	MedianDuration   float64 `json:"medianDuration"`   // in ms
+	P95Duration      float64 `json:"p95Duration"`      // in ms
+	P99Duration      float64 `json:"p99Duration"`      // in ms
+	Apdex            float64 `json:"apdex"`            // 0-1 score
+	ApdexThresholdMs int     `json:"apdexThresholdMs"` // effective Apdex T including the endpoint's offset
+	ErrorRate        float64 `json:"errorRate"`        // percentage
+	Throughput       float64 `json:"throughput"`       // requests per minute
+}
+
+// SpanBreakdownRow is the time spent in one span name across the traces of an endpoint
+type SpanBreakdownRow struct {
+	Name               string  `json:"name"`
+	AvgCountPerRequest float64 `json:"avgCountPerRequest"`
+	AvgSelfTime        float64 `json:"avgSelfTime"` // in ms, duration minus the duration of its child spans
+	P95SelfTime        float64 `json:"p95SelfTime"` // in ms
+	TimeShare          float64 `json:"timeShare"`   // percentage of the total endpoint duration
+}
+
+// SpanBreakdown shows where time goes inside an endpoint
+type SpanBreakdown struct {
+	RequestCount        int64              `json:"requestCount"`
+	AvgDuration         float64            `json:"avgDuration"`         // in ms
+	UninstrumentedShare float64            `json:"uninstrumentedShare"` // percentage of the endpoint duration not covered by any span
+	Spans               []SpanBreakdownRow `json:"spans"`
 }
 
 // EndpointTimeSeriesPoint represents a single data point in a time series for endpoint charts
@@ -54,6 +77,7 @@ type EndpointTimeSeriesPoint struct {
 
 // EndpointStackedChartResponse contains the data for rendering a stacked area chart
 type EndpointStackedChartResponse struct {
-	Endpoints []string                  `json:"endpoints"` // Top 5 + "Other"
-	Series    []EndpointTimeSeriesPoint `json:"series"`
+	Endpoints     []string                  `json:"endpoints"` // Top 5 + "Other"
+	Series        []EndpointTimeSeriesPoint `json:"series"`
+	DeployMarkers []DeployMarker            `json:"deployMarkers"`
 }
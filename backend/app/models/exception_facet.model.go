@@ -0,0 +1,22 @@
+package models
+
+// Built-in facet keys of exception groups, every other facet is an attribute key
+const (
+	ExceptionFacetServerName  = "serverName"
+	ExceptionFacetAppVersion  = "appVersion"
+	ExceptionFacetTraceType   = "traceType"
+	ExceptionFacetEnvironment = "environment"
+)
+
+type ExceptionFacetValue struct {
+	Value      string  `json:"value"`
+	Count      uint64  `json:"count"`
+	Percentage float64 `json:"percentage"` // of the occurrences that have the key
+}
+
+// ExceptionFacet is the distribution of one key across the occurrences of an exception group
+type ExceptionFacet struct {
+	Key    string                `json:"key"`
+	Total  uint64                `json:"total"`  // occurrences that have the key
+	Values []ExceptionFacetValue `json:"values"` // top values by count
+}
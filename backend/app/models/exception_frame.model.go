@@ -0,0 +1,19 @@
+package models
+
+// ExceptionFrame is a single frame of a parsed stack trace
+type ExceptionFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Module   string `json:"module"` // Go package, Java class, empty when the format has none
+	InApp    bool   `json:"inApp"`
+}
+
+// ParsedStackTrace is the exception type, message and frames read from a raw stack trace,
+// frames are ordered from the innermost call outwards
+type ParsedStackTrace struct {
+	Type    string
+	Message string
+	Frames  []ExceptionFrame
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExceptionGroupAlias merges the exceptions of ExceptionHash into the group of CanonicalHash
+type ExceptionGroupAlias struct {
+	Id            int       `json:"id"`
+	ProjectId     uuid.UUID `json:"projectId"`
+	ExceptionHash string    `json:"exceptionHash"`
+	CanonicalHash string    `json:"canonicalHash"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
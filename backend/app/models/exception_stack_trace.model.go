@@ -13,11 +13,17 @@ type ExceptionStackTrace struct {
 	TraceType string            `json:"traceType" ch:"trace_type"` // "endpoint" or "task"
 	ExceptionHash   string            `json:"exceptionHash" ch:"exception_hash"`
 	StackTrace      string            `json:"stackTrace" ch:"stack_trace"`
+	RawStackTrace   string            `json:"rawStackTrace,omitempty" ch:"raw_stack_trace"` // as received, set once source maps resolved it
+	DebugIds        map[string]string `json:"debugIds,omitempty" ch:"debug_ids"` // script URL to the debug ID of the source map built with it
 	RecordedAt      time.Time         `json:"recordedAt" ch:"recorded_at"`
 	Attributes      map[string]string `json:"attributes" ch:"attributes"`
 	AppVersion      string            `json:"appVersion" ch:"app_version"`
 	ServerName      string            `json:"serverName" ch:"server_name"`
+	Environment     string            `json:"environment" ch:"environment"`
 	IsMessage       bool              `json:"isMessage" ch:"is_message"`
+	ExceptionType   string            `json:"exceptionType" ch:"exception_type"`
+	ExceptionMessage string           `json:"exceptionMessage" ch:"exception_message"`
+	Frames          []ExceptionFrame  `json:"frames" ch:"frames"`
 }
 
 type ExceptionTrendPoint struct {
@@ -26,10 +32,13 @@ type ExceptionTrendPoint struct {
 }
 
 type ExceptionGroup struct {
-	ExceptionHash string                `json:"exceptionHash" ch:"exception_hash"`
-	StackTrace    string                `json:"stackTrace" ch:"stack_trace"`
-	LastSeen      time.Time             `json:"lastSeen" ch:"last_seen"`
-	FirstSeen     time.Time             `json:"firstSeen" ch:"first_seen"`
-	Count         uint64                `json:"count" ch:"count"`
-	HourlyTrend   []ExceptionTrendPoint `json:"hourlyTrend,omitempty"`
+	ExceptionHash    string                `json:"exceptionHash" ch:"exception_hash"`
+	StackTrace       string                `json:"stackTrace" ch:"stack_trace"`
+	LastSeen         time.Time             `json:"lastSeen" ch:"last_seen"`
+	FirstSeen        time.Time             `json:"firstSeen" ch:"first_seen"`
+	Count            uint64                `json:"count" ch:"count"`
+	FirstSeenRelease string                `json:"firstSeenRelease" ch:"first_seen_release"` // app version of the earliest occurrence
+	AffectedUsers    uint64                `json:"affectedUsers" ch:"affected_users"`          // approximate distinct users
+	ExceptionType    string                `json:"exceptionType" ch:"exception_type"`
+	HourlyTrend      []ExceptionTrendPoint `json:"hourlyTrend,omitempty"`
 }
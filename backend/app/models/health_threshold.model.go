@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HealthStatusHealthy  = "healthy"
+	HealthStatusWarning  = "warning"
+	HealthStatusCritical = "critical"
+)
+
+type HealthThreshold struct {
+	Id         int       `json:"id"`
+	ProjectId  uuid.UUID `json:"projectId"`
+	MetricType string    `json:"metricType"`
+	Warning    float64   `json:"warning"`
+	Critical   float64   `json:"critical"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type HealthThresholdLevels struct {
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+}
+
+// HealthMetricTypes lists every metric type a threshold can be configured for, in display order
+var HealthMetricTypes = []string{
+	"requests",
+	"exceptions",
+	"response_time",
+	"error_rate",
+	"cpu",
+	"memory",
+	"memory_total",
+	"go_routines",
+	"heap_objects",
+	"num_gc",
+	"gc_pause",
+}
+
+// DefaultHealthThresholds are applied when a project has no override for a metric type.
+// Metric types without a default (memory_total, num_gc, gc_pause) are informational and always healthy.
+var DefaultHealthThresholds = map[string]HealthThresholdLevels{
+	"requests":      {Warning: 100, Critical: 10},
+	"exceptions":    {Warning: 10, Critical: 50},
+	"response_time": {Warning: 200, Critical: 500},
+	"error_rate":    {Warning: 2, Critical: 5},
+	"cpu":           {Warning: 70, Critical: 90},
+	"memory":        {Warning: 700, Critical: 900},
+	"go_routines":   {Warning: 5000, Critical: 10000},
+	"heap_objects":  {Warning: 500000, Critical: 1000000},
+}
+
+// lowerIsWorseMetrics are the metric types where dropping below the threshold is the problem (less traffic may indicate issues)
+var lowerIsWorseMetrics = map[string]bool{
+	"requests": true,
+}
+
+func IsHealthMetricType(metricType string) bool {
+	for _, t := range HealthMetricTypes {
+		if t == metricType {
+			return true
+		}
+	}
+	return false
+}
+
+func IsLowerWorseHealthMetric(metricType string) bool {
+	return lowerIsWorseMetrics[metricType]
+}
+
+// HealthThresholdProfile holds the effective thresholds of a project keyed by metric type
+type HealthThresholdProfile map[string]HealthThresholdLevels
+
+func NewHealthThresholdProfile(overrides []*HealthThreshold) HealthThresholdProfile {
+	profile := make(HealthThresholdProfile, len(DefaultHealthThresholds)+len(overrides))
+	for metricType, levels := range DefaultHealthThresholds {
+		profile[metricType] = levels
+	}
+	for _, o := range overrides {
+		profile[o.MetricType] = HealthThresholdLevels{Warning: o.Warning, Critical: o.Critical}
+	}
+	return profile
+}
+
+type HealthThresholdResponse struct {
+	MetricType   string   `json:"metricType"`
+	Warning      *float64 `json:"warning"`
+	Critical     *float64 `json:"critical"`
+	LowerIsWorse bool     `json:"lowerIsWorse"`
+	IsDefault    bool     `json:"isDefault"`
+}
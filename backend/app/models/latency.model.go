@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	LatencySourceEndpoint = "endpoint"
+	LatencySourceTask     = "task"
+)
+
+// Latency buckets are log-scaled: bucket i holds durations in [LatencyBucketBase * 2^(i/LatencyBucketsPerDoubling), next bound)
+const (
+	LatencyBucketBase         = time.Millisecond // faster durations fall into bucket 0
+	LatencyBucketsPerDoubling = 2                // each bucket is √2 wider than the previous one
+	LatencyMaxBucket          = 39               // also holds everything slower, about 12 minutes and above
+)
+
+// LatencyBucketCount is the number of requests in one log-scaled latency bucket
+type LatencyBucketCount struct {
+	Bucket int
+	Count  uint64
+}
+
+// LatencyHeatmapCell is the number of requests in one latency bucket during one time interval
+type LatencyHeatmapCell struct {
+	Timestamp time.Time
+	Bucket    int
+	Count     uint64
+}
+
+type LatencyHistogramBucket struct {
+	LowerBoundMs float64 `json:"lowerBoundMs"`
+	UpperBoundMs float64 `json:"upperBoundMs"`
+	Count        uint64  `json:"count"`
+}
+
+type LatencyHistogram struct {
+	Total   uint64                   `json:"total"`
+	Buckets []LatencyHistogramBucket `json:"buckets"` // contiguous from the fastest to the slowest non-empty bucket
+}
+
+// LatencyHeatmap is a time by latency matrix, Counts[i][j] is the number of requests
+// that started in Timestamps[i] and fell into Buckets[j]
+type LatencyHeatmap struct {
+	IntervalMinutes int                      `json:"intervalMinutes"`
+	Timestamps      []time.Time              `json:"timestamps"`
+	Buckets         []LatencyHistogramBucket `json:"buckets"` // Count is the total of the row across the range
+	Counts          [][]uint64               `json:"counts"`
+}
@@ -7,11 +7,12 @@ import (
 )
 
 type MetricRecord struct {
-	ProjectId  uuid.UUID `json:"projectId" ch:"project_id"`
-	Name       string    `json:"name" ch:"name"`
-	Value      float64   `json:"value" ch:"value"`
-	RecordedAt time.Time `json:"recordedAt" ch:"recorded_at"`
-	ServerName string    `json:"serverName" ch:"server_name"`
+	ProjectId   uuid.UUID `json:"projectId" ch:"project_id"`
+	Name        string    `json:"name" ch:"name"`
+	Value       float64   `json:"value" ch:"value"`
+	RecordedAt  time.Time `json:"recordedAt" ch:"recorded_at"`
+	ServerName  string    `json:"serverName" ch:"server_name"`
+	Environment string    `json:"environment" ch:"environment"`
 }
 
 const (
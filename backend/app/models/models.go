@@ -17,6 +17,13 @@ func Init() {
 	lit.RegisterModel[UserOrganizationResponse](lit.PostgreSQL)
 	lit.RegisterModel[CountResult](lit.PostgreSQL)
 	lit.RegisterModel[SourceMap](lit.PostgreSQL)
+	lit.RegisterModel[SourceMapVersion](lit.PostgreSQL)
+	lit.RegisterModel[HealthThreshold](lit.PostgreSQL)
+	lit.RegisterModel[ProjectSetting](lit.PostgreSQL)
+	lit.RegisterModel[Slo](lit.PostgreSQL)
+	lit.RegisterModel[Release](lit.PostgreSQL)
+	lit.RegisterModel[ReleaseWithSourceMaps](lit.PostgreSQL)
+	lit.RegisterModel[ExceptionGroupAlias](lit.PostgreSQL)
 
 	for _, register := range ExtensionModelRegistrations {
 		register()
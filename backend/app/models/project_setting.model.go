@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultApdexThresholdMs is the Apdex T used for projects that haven't configured one
+const DefaultApdexThresholdMs = 500
+
+// DefaultUserIdentityAttribute is the attribute holding the user id for projects that haven't configured one
+const DefaultUserIdentityAttribute = "user.id"
+
+type ProjectSetting struct {
+	Id               int       `json:"id"`
+	ProjectId        uuid.UUID `json:"projectId"`
+	ApdexThresholdMs int       `json:"apdexThresholdMs"`
+	// UserIdentityAttribute is the attribute used to count affected users, OTLP's enduser.id is used when it's missing
+	UserIdentityAttribute string `json:"userIdentityAttribute"`
+	// InAppPrefixes is a comma separated list of module and path prefixes of the project's own code
+	InAppPrefixes string    `json:"inAppPrefixes"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// InAppPrefixList returns the configured in-app prefixes, empty when the defaults apply
+func (s *ProjectSetting) InAppPrefixList() []string {
+	var prefixes []string
+	for _, prefix := range strings.Split(s.InAppPrefixes, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// DefaultProjectSetting returns the settings used for a project that has no stored row
+func DefaultProjectSetting(projectId uuid.UUID) *ProjectSetting {
+	return &ProjectSetting{
+		ProjectId:             projectId,
+		ApdexThresholdMs:      DefaultApdexThresholdMs,
+		UserIdentityAttribute: DefaultUserIdentityAttribute,
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryStats aggregates the executions of one normalized database statement
+type QueryStats struct {
+	Statement     string        `json:"statement"`
+	DbSystem      string        `json:"dbSystem"`
+	Count         uint64        `json:"count"`
+	TotalDuration time.Duration `json:"totalDuration"`
+	AvgDuration   time.Duration `json:"avgDuration"`
+	P95Duration   time.Duration `json:"p95Duration"`
+	TraceCount    uint64        `json:"traceCount"`
+	CallsPerTrace float64       `json:"callsPerTrace"`
+	LastSeen      time.Time     `json:"lastSeen"`
+	Callers       []string      `json:"callers"` // endpoints and tasks that ran the statement
+}
+
+// NPlusOneQuery is a normalized statement that repeated many times inside single traces
+type NPlusOneQuery struct {
+	Statement      string        `json:"statement"`
+	DbSystem       string        `json:"dbSystem"`
+	AffectedTraces uint64        `json:"affectedTraces"`
+	MaxRepetitions uint64        `json:"maxRepetitions"`
+	AvgRepetitions float64       `json:"avgRepetitions"`
+	TotalDuration  time.Duration `json:"totalDuration"`  // time spent in the repeated statement across the affected traces
+	ExampleTraceId uuid.UUID     `json:"exampleTraceId"` // trace with the most repetitions
+	Callers        []string      `json:"callers"`
+}
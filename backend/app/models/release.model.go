@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Release struct {
+	Id          int       `json:"id"`
+	ProjectId   uuid.UUID `json:"projectId"`
+	Version     string    `json:"version"`
+	Environment string    `json:"environment"`
+	CommitSha   string    `json:"commitSha"`
+	ReleasedAt  time.Time `json:"releasedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ReleaseWithSourceMaps is a release with the number of source map files uploaded for its version
+type ReleaseWithSourceMaps struct {
+	Id             int       `json:"id"`
+	ProjectId      uuid.UUID `json:"projectId"`
+	Version        string    `json:"version"`
+	Environment    string    `json:"environment"`
+	CommitSha      string    `json:"commitSha"`
+	ReleasedAt     time.Time `json:"releasedAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+	SourceMapCount int       `json:"sourceMapCount" lit:"source_map_count"`
+}
+
+// DeployMarker is a release shown on charts at the time it was deployed
+type DeployMarker struct {
+	Version     string    `json:"version"`
+	Environment string    `json:"environment"`
+	CommitSha   string    `json:"commitSha"`
+	Timestamp   time.Time `json:"timestamp"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+const (
+	ReleaseDeltaNew     = "new"
+	ReleaseDeltaRemoved = "removed"
+	ReleaseDeltaChanged = "changed"
+)
+
+// VersionEndpointStats are the aggregate stats of a single endpoint within one app version
+type VersionEndpointStats struct {
+	AppVersion  string        `json:"appVersion"`
+	Endpoint    string        `json:"endpoint"`
+	Count       uint64        `json:"count"`
+	P50Duration time.Duration `json:"p50Duration"`
+	P95Duration time.Duration `json:"p95Duration"`
+	P99Duration time.Duration `json:"p99Duration"`
+	ErrorRate   float64       `json:"errorRate"` // percentage
+}
+
+type EndpointReleaseDelta struct {
+	Endpoint       string                `json:"endpoint"`
+	Status         string                `json:"status"` // new, removed, changed
+	Base           *VersionEndpointStats `json:"base"`
+	Target         *VersionEndpointStats `json:"target"`
+	CountDelta     int64                 `json:"countDelta"`
+	P50Delta       time.Duration         `json:"p50Delta"`
+	P95Delta       time.Duration         `json:"p95Delta"`
+	P99Delta       time.Duration         `json:"p99Delta"`
+	ErrorRateDelta float64               `json:"errorRateDelta"` // percentage points
+}
+
+// VersionExceptionCounts are the occurrences of an exception hash in the base and target versions
+type VersionExceptionCounts struct {
+	ExceptionHash string `json:"exceptionHash"`
+	StackTrace    string `json:"stackTrace"`
+	IsMessage     bool   `json:"isMessage"`
+	BaseCount     uint64 `json:"baseCount"`
+	TargetCount   uint64 `json:"targetCount"`
+}
+
+type ReleaseComparison struct {
+	BaseVersion         string                   `json:"baseVersion"`
+	TargetVersion       string                   `json:"targetVersion"`
+	Endpoints           []EndpointReleaseDelta   `json:"endpoints"`
+	NewExceptions       []VersionExceptionCounts `json:"newExceptions"`
+	GoneExceptions      []VersionExceptionCounts `json:"goneExceptions"`
+	IncreasedExceptions []VersionExceptionCounts `json:"increasedExceptions"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	ServiceNodeTypeService  = "service"
+	ServiceNodeTypeExternal = "external"
+)
+
+// ServiceEdge aggregates the calls from one service to another service or an external dependency
+type ServiceEdge struct {
+	Source      string        `json:"source"`
+	Target      string        `json:"target"`
+	TargetType  string        `json:"targetType"` // service or external
+	Calls       uint64        `json:"calls"`
+	Errors      uint64        `json:"errors"`
+	ErrorRate   float64       `json:"errorRate"` // percentage
+	P95Duration time.Duration `json:"p95Duration"`
+}
+
+type ServiceNode struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // service or external
+}
+
+type ServiceMap struct {
+	Nodes []ServiceNode `json:"nodes"`
+	Edges []ServiceEdge `json:"edges"`
+}
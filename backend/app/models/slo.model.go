@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SloTypeAvailability = "availability"
+	SloTypeLatency      = "latency"
+)
+
+// SloWindowDays are the supported rolling windows
+var SloWindowDays = []int{7, 28, 30}
+
+// Slo is a service level objective computed from the endpoints table.
+// An empty Endpoint means the objective covers every endpoint of the project.
+type Slo struct {
+	Id                 int       `json:"id"`
+	ProjectId          uuid.UUID `json:"projectId"`
+	Name               string    `json:"name"`
+	Endpoint           string    `json:"endpoint"`
+	SloType            string    `json:"sloType"`
+	Target             float64   `json:"target"`             // percentage, e.g. 99.9
+	LatencyThresholdMs int       `json:"latencyThresholdMs"` // only used by latency SLOs
+	WindowDays         int       `json:"windowDays"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+func (s *Slo) Window() time.Duration {
+	return time.Duration(s.WindowDays) * 24 * time.Hour
+}
+
+// SloCounts holds the number of good and total events for a time range
+type SloCounts struct {
+	Good  uint64 `json:"good"`
+	Total uint64 `json:"total"`
+}
+
+type SloErrorBudget struct {
+	Allowed   float64 `json:"allowed"`   // number of bad events the window can absorb
+	Consumed  uint64  `json:"consumed"`  // number of bad events so far
+	Remaining float64 `json:"remaining"` // fraction of the budget left, negative when overspent
+}
+
+type SloBurnRate struct {
+	Window string  `json:"window"`
+	Rate   float64 `json:"rate"` // 1.0 means the budget is consumed exactly over the SLO window
+	Good   uint64  `json:"good"`
+	Total  uint64  `json:"total"`
+}
+
+type SloStatus struct {
+	Slo         *Slo           `json:"slo"`
+	Sli         float64        `json:"sli"` // percentage of good events in the window
+	Good        uint64         `json:"good"`
+	Total       uint64         `json:"total"`
+	ErrorBudget SloErrorBudget `json:"errorBudget"`
+	BurnRates   []SloBurnRate  `json:"burnRates"`
+	Status      string         `json:"status"` // healthy, warning, critical
+}
+
+type SloHistoryPoint struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Sli             float64   `json:"sli"`             // percentage of good events in the bucket
+	BudgetRemaining float64   `json:"budgetRemaining"` // fraction of the budget left at the end of the bucket
+	Good            uint64    `json:"good"`
+	Total           uint64    `json:"total"`
+}
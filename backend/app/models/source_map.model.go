@@ -14,4 +14,25 @@ type SourceMap struct {
 	StorageKey string   `json:"storageKey"`
 	FileSize  int64     `json:"fileSize"`
 	UploadedAt time.Time `json:"uploadedAt"`
+	// DebugId links the map to the minified file built with it, empty when the build didn't inject debug IDs
+	DebugId   string    `json:"debugId"`
+}
+
+// SourceMapVersion is a version with the source map files uploaded for it
+type SourceMapVersion struct {
+	Version        string    `json:"version"`
+	FileCount      int       `json:"fileCount" lit:"file_count"`
+	TotalSize      int64     `json:"totalSize" lit:"total_size"`
+	LastUploadedAt time.Time `json:"lastUploadedAt" lit:"last_uploaded_at"`
+}
+
+// SourceMapSummary describes the contents of a source map file
+type SourceMapSummary struct {
+	// File is the minified file the map covers, taken from the map or from the uploaded file name
+	File                   string   `json:"file"`
+	DebugId                string   `json:"debugId"`
+	Sources                []string `json:"sources"`
+	SourcesContentEmbedded bool     `json:"sourcesContentEmbedded"`
+	// MissingContent lists the sources without embedded content, function names can't be recovered for their frames
+	MissingContent []string `json:"missingContent"`
 }
@@ -7,11 +7,24 @@ import (
 )
 
 type Span struct {
-	Id            uuid.UUID     `json:"id" ch:"id"`
-	TraceId       uuid.UUID     `json:"traceId" ch:"trace_id"`
-	ProjectId     uuid.UUID     `json:"projectId" ch:"project_id"`
-	Name          string        `json:"name" ch:"name"`
-	StartTime     time.Time     `json:"startTime" ch:"start_time"`
-	Duration      time.Duration `json:"duration" ch:"duration"`
-	RecordedAt    time.Time     `json:"recordedAt" ch:"recorded_at"`
+	Id             uuid.UUID         `json:"id" ch:"id"`
+	TraceId        uuid.UUID         `json:"traceId" ch:"trace_id"`
+	ProjectId      uuid.UUID         `json:"projectId" ch:"project_id"`
+	Name           string            `json:"name" ch:"name"`
+	StartTime      time.Time         `json:"startTime" ch:"start_time"`
+	Duration       time.Duration     `json:"duration" ch:"duration"`
+	RecordedAt     time.Time         `json:"recordedAt" ch:"recorded_at"`
+	Environment    string            `json:"environment" ch:"environment"`
+	ParentId       *uuid.UUID        `json:"parentId" ch:"parent_id"`       // nil when the SDK doesn't report the span hierarchy
+	ServiceName    string            `json:"serviceName" ch:"service_name"` // OTLP service.name of the emitting service
+	Kind           string            `json:"kind" ch:"kind"`                // server, client, producer, consumer or internal
+	Attributes     map[string]string `json:"attributes" ch:"attributes"`
+	IsError        bool              `json:"isError" ch:"is_error"`
+	PeerService    string            `json:"peerService" ch:"peer_service"` // external dependency called by client spans, e.g. postgresql or api.stripe.com
+	DbSystem       string            `json:"dbSystem" ch:"db_system"`
+	DbStatement    string            `json:"dbStatement" ch:"db_statement"` // normalized statement, literals replaced with ?
+	HttpMethod     string            `json:"httpMethod" ch:"http_method"`   // set on outgoing HTTP calls of client spans
+	HttpHost       string            `json:"httpHost" ch:"http_host"`
+	HttpRoute      string            `json:"httpRoute" ch:"http_route"` // url template, ids replaced with {id}
+	HttpStatusCode int16             `json:"httpStatusCode" ch:"http_status_code"`
 }
@@ -7,15 +7,18 @@ import (
 )
 
 type Task struct {
-	Id         uuid.UUID         `json:"id" ch:"id"`
-	ProjectId  uuid.UUID         `json:"projectId" ch:"project_id"`
-	TaskName   string            `json:"taskName" ch:"task_name"`
-	Duration   time.Duration     `json:"duration" ch:"duration"`
-	RecordedAt time.Time         `json:"recordedAt" ch:"recorded_at"`
-	ClientIP   string            `json:"clientIP" ch:"client_ip"`
-	Attributes map[string]string `json:"attributes" ch:"attributes"`
-	AppVersion string            `json:"appVersion" ch:"app_version"`
-	ServerName string            `json:"serverName" ch:"server_name"`
+	Id          uuid.UUID         `json:"id" ch:"id"`
+	ProjectId   uuid.UUID         `json:"projectId" ch:"project_id"`
+	TaskName    string            `json:"taskName" ch:"task_name"`
+	Duration    time.Duration     `json:"duration" ch:"duration"`
+	RecordedAt  time.Time         `json:"recordedAt" ch:"recorded_at"`
+	ClientIP    string            `json:"clientIP" ch:"client_ip"`
+	Attributes  map[string]string `json:"attributes" ch:"attributes"`
+	AppVersion  string            `json:"appVersion" ch:"app_version"`
+	ServerName  string            `json:"serverName" ch:"server_name"`
+	Environment string            `json:"environment" ch:"environment"`
+	// ResourceAttributes are the OTLP resource attributes (host.name, container.id, k8s.pod.name, ...)
+	ResourceAttributes map[string]string `json:"resourceAttributes" ch:"resource_attributes"`
 }
 
 type TaskStats struct {
@@ -0,0 +1,10 @@
+package models
+
+// TelemetryFilter narrows list, grouped and dashboard queries to a subset of the collected telemetry.
+// Empty fields don't filter.
+type TelemetryFilter struct {
+	Environment string `json:"environment"`
+	// ResourceAttributes matches OTLP resource attributes exactly (e.g. "k8s.pod.name"),
+	// only endpoints and tasks store them so other queries ignore it
+	ResourceAttributes map[string]string `json:"resourceAttributes"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TraceEntryTypeEndpoint = "endpoint"
+	TraceEntryTypeTask     = "task"
+	TraceEntryTypeSpan     = "span"
+)
+
+// TraceEntry is a single row of the distributed trace waterfall
+type TraceEntry struct {
+	Id          uuid.UUID     `json:"id"`
+	ParentId    *uuid.UUID    `json:"parentId"`
+	ProjectId   uuid.UUID     `json:"projectId"`
+	ProjectName string        `json:"projectName"`
+	ServiceName string        `json:"serviceName"`
+	Type        string        `json:"type"` // endpoint, task or span
+	Kind        string        `json:"kind"` // span kind, empty for endpoints and tasks
+	Name        string        `json:"name"`
+	StartTime   time.Time     `json:"startTime"`
+	Duration    time.Duration `json:"duration"`
+	Offset      time.Duration `json:"offset"` // start relative to the beginning of the trace
+	Depth       int           `json:"depth"`
+	StatusCode  int16         `json:"statusCode,omitempty"`
+	// Orphan marks entries whose parent isn't part of the trace, they're listed after the connected entries
+	Orphan bool `json:"orphan,omitempty"`
+}
+
+// TraceWaterfall is a trace assembled from every project of the organization that reported it
+type TraceWaterfall struct {
+	TraceId    uuid.UUID             `json:"traceId"`
+	StartTime  time.Time             `json:"startTime"`
+	Duration   time.Duration         `json:"duration"`
+	Services   []string              `json:"services"`
+	Entries    []TraceEntry          `json:"entries"` // depth-first, siblings ordered by start time
+	Exceptions []ExceptionStackTrace `json:"exceptions"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	AttributeOperatorEquals         = "eq"
+	AttributeOperatorNotEquals      = "neq"
+	AttributeOperatorContains       = "contains"
+	AttributeOperatorNotContains    = "not_contains"
+	AttributeOperatorExists         = "exists"
+	AttributeOperatorNotExists      = "not_exists"
+	AttributeOperatorGreaterThan    = "gt"
+	AttributeOperatorGreaterOrEqual = "gte"
+	AttributeOperatorLessThan       = "lt"
+	AttributeOperatorLessOrEqual    = "lte"
+)
+
+// AttributeFilter matches a trace attribute, Value is ignored by exists and not_exists
+// and has to be a number for the comparison operators
+type AttributeFilter struct {
+	Key      string `json:"key"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// StatusCodeRange is an inclusive range of HTTP status codes
+type StatusCodeRange struct {
+	Min int16
+	Max int16
+}
+
+// TraceSearchQuery holds the structured filters of the trace search, zero values don't filter
+type TraceSearchQuery struct {
+	Name        string // endpoint or task name
+	StatusCodes []StatusCodeRange
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	ServerName  string
+	AppVersion  string
+	ClientIP    string
+	Attributes  []AttributeFilter
+	TelemetryFilter
+}
@@ -81,3 +81,11 @@ func ExecuteTransaction[T any](f func(tx *sql.Tx) (T, error)) (T, error) {
 
 	return result, nil
 }
+
+// TryAdvisoryXactLock takes the transaction level advisory lock identified by key without waiting, it's released
+// when the transaction ends. It returns false when another session holds the lock.
+func TryAdvisoryXactLock(tx *sql.Tx, key int64) (bool, error) {
+	var locked bool
+	err := tx.QueryRow("SELECT pg_try_advisory_xact_lock($1)", key).Scan(&locked)
+	return locked, err
+}
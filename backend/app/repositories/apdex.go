@@ -0,0 +1,54 @@
+package repositories
+
+import "fmt"
+
+// Apdex follows the standard definition with a project-level threshold T:
+//   - satisfied:  duration <= T
+//   - tolerating: T < duration <= 4T
+//   - frustrated: duration > 4T, or the request failed with a 5xx
+//
+// Per-endpoint overrides from slow_endpoints.offset_ms are added to T, so a query using
+// these helpers must expose the endpoint's offset as an offset_ms column (0 when none is set).
+
+// apdexThresholdExpr returns the effective T in nanoseconds for the current row
+func apdexThresholdExpr(apdexThresholdMs int) string {
+	return fmt.Sprintf("((%d + toInt64(offset_ms)) * 1000000)", apdexThresholdMs)
+}
+
+// apdexCountColumns returns the satisfied_count, tolerating_count and bad_count aggregate columns
+func apdexCountColumns(apdexThresholdMs int) string {
+	t := apdexThresholdExpr(apdexThresholdMs)
+	return fmt.Sprintf(`countIf(duration <= %[1]s
+				AND status_code < 500) as satisfied_count,
+			countIf(duration > %[1]s
+				AND duration <= 4 * %[1]s
+				AND status_code < 500) as tolerating_count,
+			countIf(duration > 4 * %[1]s
+				OR status_code >= 500) as bad_count`, t)
+}
+
+// apdexScoreExpr returns an aggregate expression computing the Apdex score (0-1) directly
+func apdexScoreExpr(apdexThresholdMs int) string {
+	t := apdexThresholdExpr(apdexThresholdMs)
+	return fmt.Sprintf(`if(count() > 0,
+			(countIf(duration <= %[1]s AND status_code < 500) +
+			countIf(duration > %[1]s AND duration <= 4 * %[1]s AND status_code < 500) * 0.5) / count(),
+			0)`, t)
+}
+
+// apdexScoreFromCountsExpr returns the Apdex score computed from the columns of apdexCountColumns
+func apdexScoreFromCountsExpr(totalColumn string) string {
+	return fmt.Sprintf("if(%[1]s > 0, (satisfied_count + tolerating_count * 0.5) / %[1]s, 0)", totalColumn)
+}
+
+// apdexScore computes the Apdex score from already aggregated counts
+func apdexScore(total, satisfiedCount, toleratingCount uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(satisfiedCount) + float64(toleratingCount)*0.5) / float64(total)
+}
+
+// slowEndpointsJoin joins the per-endpoint Apdex offsets onto the endpoints table aliased as e
+const slowEndpointsJoin = `LEFT JOIN (SELECT * FROM slow_endpoints FINAL) AS s
+				ON e.endpoint = s.endpoint AND e.project_id = s.project_id`
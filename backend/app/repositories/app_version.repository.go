@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"backend/app/chdb"
+	"backend/app/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type appVersionRepository struct{}
+
+// FindRecentVersions returns app versions seen in the range across endpoints, tasks and exceptions, newest first by first occurrence
+func (a *appVersionRepository) FindRecentVersions(ctx context.Context, projectId uuid.UUID, start, end time.Time, limit int, filter models.TelemetryFilter) ([]string, error) {
+	filterSQL, filterArgs := filterClause(filter, "")
+	branchArgs := withFilterArgs([]interface{}{projectId, start, end}, filterArgs)
+
+	query := `SELECT app_version, min(first_seen) as first_seen
+	FROM (
+		SELECT app_version, min(recorded_at) as first_seen FROM endpoints
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND app_version != ''` + filterSQL + `
+		GROUP BY app_version
+		UNION ALL
+		SELECT app_version, min(recorded_at) as first_seen FROM tasks
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND app_version != ''` + filterSQL + `
+		GROUP BY app_version
+		UNION ALL
+		SELECT app_version, min(recorded_at) as first_seen FROM exception_stack_traces
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND app_version != ''` + filterSQL + `
+		GROUP BY app_version
+	)
+	GROUP BY app_version
+	ORDER BY first_seen DESC
+	LIMIT ?`
+
+	var args []interface{}
+	for i := 0; i < 3; i++ {
+		args = append(args, branchArgs...)
+	}
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []string
+	for rows.Next() {
+		var version string
+		var firstSeen time.Time
+		if err := rows.Scan(&version, &firstSeen); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+var AppVersionRepository = appVersionRepository{}
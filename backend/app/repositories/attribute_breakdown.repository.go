@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"backend/app/chdb"
+	"backend/app/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type attributeBreakdownRepository struct{}
+
+// FindEndpointBreakdown groups the endpoint traces matching the search by the value of an attribute, top values first
+func (r *attributeBreakdownRepository) FindEndpointBreakdown(ctx context.Context, projectId uuid.UUID, key string, fromDate, toDate time.Time, search models.TraceSearchQuery, limit int) (*models.AttributeBreakdown, error) {
+	searchSQL, searchArgs := traceSearchClause(search, "endpoint", true)
+	whereClause := "project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND mapContains(attributes_map, ?)" + searchSQL
+	whereArgs := withFilterArgs([]interface{}{projectId, fromDate, toDate, key}, searchArgs)
+
+	query := `SELECT
+		attributes_map[?] as value,
+		count() as total_count,
+		countIf(status_code >= 500) * 100.0 / count() as error_rate,
+		quantile(0.5)(duration) / 1000000 as p50_duration_ms,
+		quantile(0.95)(duration) / 1000000 as p95_duration_ms,
+		quantile(0.99)(duration) / 1000000 as p99_duration_ms
+	FROM endpoints
+	WHERE ` + whereClause + `
+	GROUP BY value
+	ORDER BY total_count DESC
+	LIMIT ?`
+
+	return r.findBreakdown(ctx, models.BreakdownSourceEndpoint, key, "SELECT count() FROM endpoints WHERE "+whereClause, query, nil, whereArgs, limit)
+}
+
+// FindTaskBreakdown groups the task runs matching the search by the value of an attribute, a run failed when it reported an exception
+func (r *attributeBreakdownRepository) FindTaskBreakdown(ctx context.Context, projectId uuid.UUID, key string, fromDate, toDate time.Time, search models.TraceSearchQuery, limit int) (*models.AttributeBreakdown, error) {
+	searchSQL, searchArgs := traceSearchClause(search, "task_name", false)
+	whereClause := "project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND mapContains(attributes_map, ?)" + searchSQL
+	whereArgs := withFilterArgs([]interface{}{projectId, fromDate, toDate, key}, searchArgs)
+
+	// exceptions are recorded while the task runs, after it started
+	query := `SELECT
+		attributes_map[?] as value,
+		count() as total_count,
+		countIf(f.failed = 1) * 100.0 / count() as error_rate,
+		quantile(0.5)(duration) / 1000000 as p50_duration_ms,
+		quantile(0.95)(duration) / 1000000 as p95_duration_ms,
+		quantile(0.99)(duration) / 1000000 as p99_duration_ms
+	FROM tasks
+	LEFT JOIN (
+		SELECT DISTINCT assumeNotNull(trace_id) as exception_trace_id, 1 as failed
+		FROM exception_stack_traces
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND trace_type = 'task' AND is_message = 0 AND trace_id IS NOT NULL
+	) f ON tasks.id = f.exception_trace_id
+	WHERE ` + whereClause + `
+	GROUP BY value
+	ORDER BY total_count DESC
+	LIMIT ?`
+
+	joinArgs := []interface{}{projectId, fromDate, toDate.Add(time.Hour)}
+	return r.findBreakdown(ctx, models.BreakdownSourceTask, key, "SELECT count() FROM tasks WHERE "+whereClause, query, joinArgs, whereArgs, limit)
+}
+
+// FindExceptionBreakdown groups the exception occurrences by the value of an attribute, optionally for a single exception group
+func (r *attributeBreakdownRepository) FindExceptionBreakdown(ctx context.Context, projectId uuid.UUID, key string, fromDate, toDate time.Time, exceptionHash string, aliases map[string]string, filter models.TelemetryFilter, limit int) (*models.AttributeBreakdown, error) {
+	// merged exception hashes count as the group they were merged into
+	groupHash, groupArgs := exceptionGroupHashExpr("exception_hash", aliases)
+
+	whereClause := "project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND mapContains(attributes_map, ?)"
+	whereArgs := []interface{}{projectId, fromDate, toDate, key}
+	if exceptionHash != "" {
+		whereClause += " AND " + groupHash + " = ?"
+		whereArgs = append(append(whereArgs, groupArgs...), exceptionHash)
+	}
+	filterSQL, filterArgs := filterClause(filter, "")
+	whereClause += filterSQL
+	whereArgs = append(whereArgs, filterArgs...)
+
+	var total uint64
+	if err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM exception_stack_traces WHERE "+whereClause, whereArgs...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT
+		attributes_map[?] as value,
+		count() as total_count,
+		uniq(` + groupHash + `) as issues
+	FROM exception_stack_traces
+	WHERE ` + whereClause + `
+	GROUP BY value
+	ORDER BY total_count DESC
+	LIMIT ?`
+
+	args := append(append([]interface{}{key}, groupArgs...), whereArgs...)
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breakdown := &models.AttributeBreakdown{Key: key, Source: models.BreakdownSourceException, Total: total, Values: []models.AttributeBreakdownRow{}}
+	for rows.Next() {
+		var row models.AttributeBreakdownRow
+		if err := rows.Scan(&row.Value, &row.Count, &row.Issues); err != nil {
+			return nil, err
+		}
+		if total > 0 {
+			row.Share = float64(row.Count) / float64(total) * 100
+		}
+		breakdown.Values = append(breakdown.Values, row)
+	}
+
+	return breakdown, nil
+}
+
+// findBreakdown runs the total and the grouped query shared by endpoints and tasks,
+// joinArgs belong to a join placed between the selected value and the WHERE clause
+func (r *attributeBreakdownRepository) findBreakdown(ctx context.Context, source, key, countQuery, query string, joinArgs, whereArgs []interface{}, limit int) (*models.AttributeBreakdown, error) {
+	var total uint64
+	if err := (*chdb.Conn).QueryRow(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	args := append([]interface{}{key}, joinArgs...)
+	args = append(args, whereArgs...)
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breakdown := &models.AttributeBreakdown{Key: key, Source: source, Total: total, Values: []models.AttributeBreakdownRow{}}
+	for rows.Next() {
+		var row models.AttributeBreakdownRow
+		if err := rows.Scan(&row.Value, &row.Count, &row.ErrorRate, &row.P50Duration, &row.P95Duration, &row.P99Duration); err != nil {
+			return nil, err
+		}
+		if total > 0 {
+			row.Share = float64(row.Count) / float64(total) * 100
+		}
+		breakdown.Values = append(breakdown.Values, row)
+	}
+
+	return breakdown, nil
+}
+
+var AttributeBreakdownRepository = attributeBreakdownRepository{}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"backend/app/chdb"
+	"backend/app/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type dependencyRepository struct{}
+
+// FindOutbound groups the outgoing HTTP calls of the project by host, method and route template, busiest first
+func (r *dependencyRepository) FindOutbound(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, search string, filter models.TelemetryFilter) ([]models.OutboundDependency, error) {
+	whereClause := "s.project_id = ? AND s.recorded_at >= ? AND s.recorded_at <= ? AND s.http_method != ''"
+	args := []interface{}{projectId, fromDate, toDate}
+
+	if search != "" {
+		whereClause += " AND (positionCaseInsensitive(s.http_host, ?) > 0 OR positionCaseInsensitive(s.http_route, ?) > 0)"
+		args = append(args, search, search)
+	}
+
+	filterSQL, filterArgs := filterClause(filter, "s")
+	whereClause += filterSQL
+	args = append(args, filterArgs...)
+
+	query := `SELECT
+		s.http_host,
+		s.http_method,
+		s.http_route,
+		count() as calls,
+		countIf(s.is_error = 1 OR s.http_status_code >= 500) as errors,
+		quantile(0.5)(s.duration),
+		quantile(0.95)(s.duration),
+		quantile(0.99)(s.duration),
+		max(s.recorded_at),
+		groupUniqArrayIf(5)(callers.name, callers.name != '')
+	FROM spans s
+	` + traceCallersJoin("s") + `
+	WHERE ` + whereClause + `
+	GROUP BY s.http_host, s.http_method, s.http_route
+	ORDER BY calls DESC
+	LIMIT 200`
+
+	queryArgs := []interface{}{projectId, fromDate, toDate, projectId, fromDate, toDate}
+	queryArgs = append(queryArgs, args...)
+
+	rows, err := (*chdb.Conn).Query(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dependencies []models.OutboundDependency
+	for rows.Next() {
+		var d models.OutboundDependency
+		var p50, p95, p99 float64
+		if err := rows.Scan(&d.Host, &d.Method, &d.Route, &d.Calls, &d.Errors, &p50, &p95, &p99, &d.LastSeen, &d.Callers); err != nil {
+			return nil, err
+		}
+		d.P50Duration = time.Duration(p50)
+		d.P95Duration = time.Duration(p95)
+		d.P99Duration = time.Duration(p99)
+		if d.Calls > 0 {
+			d.ErrorRate = float64(d.Errors) / float64(d.Calls) * 100
+		}
+		dependencies = append(dependencies, d)
+	}
+
+	return dependencies, nil
+}
+
+// SeriesByInterval returns the volume, error rate and p95 latency of the calls to a host, optionally a single route
+func (r *dependencyRepository) SeriesByInterval(ctx context.Context, projectId uuid.UUID, host, route string, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.DependencyTimeSeriesPoint, error) {
+	whereClause := "project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND http_method != '' AND http_host = ?"
+	args := []interface{}{intervalMinutes, projectId, start, end, host}
+	if route != "" {
+		whereClause += " AND http_route = ?"
+		args = append(args, route)
+	}
+
+	filterSQL, filterArgs := filterClause(filter, "")
+
+	query := `SELECT
+		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
+		count() as calls,
+		countIf(is_error = 1 OR http_status_code >= 500) * 100.0 / count() as error_rate,
+		quantile(0.95)(duration) / 1000000 as p95_duration_ms
+	FROM spans
+	WHERE ` + whereClause + filterSQL + `
+	GROUP BY bucket
+	ORDER BY bucket ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs(args, filterArgs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []models.DependencyTimeSeriesPoint
+	for rows.Next() {
+		var p models.DependencyTimeSeriesPoint
+		if err := rows.Scan(&p.Timestamp, &p.Calls, &p.ErrorRate, &p.P95DurationMs); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
+var DependencyRepository = dependencyRepository{}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -16,7 +17,7 @@ import (
 type endpointRepository struct{}
 
 func (e *endpointRepository) InsertAsync(ctx context.Context, lines []models.Endpoint) error {
-	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO endpoints (id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name)")
+	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO endpoints (id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name, environment, resource_attributes)")
 	if err != nil {
 		return err
 	}
@@ -27,22 +28,26 @@ func (e *endpointRepository) InsertAsync(ctx context.Context, lines []models.End
 				attributesJSON = string(attributesBytes)
 			}
 		}
-		if err := batch.Append(t.Id, t.ProjectId, t.Endpoint, t.Duration, t.RecordedAt, t.StatusCode, t.BodySize, t.ClientIP, attributesJSON, t.AppVersion, t.ServerName); err != nil {
+		if err := batch.Append(t.Id, t.ProjectId, t.Endpoint, t.Duration, t.RecordedAt, t.StatusCode, t.BodySize, t.ClientIP, attributesJSON, t.AppVersion, t.ServerName, t.Environment, resourceAttributesOrEmpty(t.ResourceAttributes)); err != nil {
 			return err
 		}
 	}
 	return batch.Send()
 }
 
-func (e *endpointRepository) CountBetween(ctx context.Context, projectId uuid.UUID, start, end time.Time) (int64, error) {
+func (e *endpointRepository) CountBetween(ctx context.Context, projectId uuid.UUID, start, end time.Time, filter models.TelemetryFilter) (int64, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
 	var count uint64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, start, end).Scan(&count)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, withFilterArgs([]interface{}{projectId, start, end}, filterArgs)...).Scan(&count)
 	return int64(count), err
 }
 
-func (e *endpointRepository) FindAll(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string) ([]models.Endpoint, int64, error) {
+func (e *endpointRepository) FindAll(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, filter models.TelemetryFilter) ([]models.Endpoint, int64, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+	args := withFilterArgs([]interface{}{projectId, fromDate, toDate}, filterArgs)
+
 	var count uint64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, fromDate, toDate).Scan(&count)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, args...).Scan(&count)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -60,8 +65,8 @@ func (e *endpointRepository) FindAll(ctx context.Context, projectId uuid.UUID, f
 		orderBy = "recorded_at"
 	}
 
-	query := "SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? ORDER BY " + orderBy + " DESC LIMIT ? OFFSET ?"
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, fromDate, toDate, pageSize, offset)
+	query := "SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name, environment FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?" + filterSQL + " ORDER BY " + orderBy + " DESC LIMIT ? OFFSET ?"
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -71,7 +76,7 @@ func (e *endpointRepository) FindAll(ctx context.Context, projectId uuid.UUID, f
 	for rows.Next() {
 		var t models.Endpoint
 		var attributesJSON string
-		if err := rows.Scan(&t.Id, &t.ProjectId, &t.Endpoint, &t.Duration, &t.RecordedAt, &t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName); err != nil {
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.Endpoint, &t.Duration, &t.RecordedAt, &t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment); err != nil {
 			return nil, 0, err
 		}
 		if attributesJSON != "" && attributesJSON != "{}" {
@@ -85,7 +90,59 @@ func (e *endpointRepository) FindAll(ctx context.Context, projectId uuid.UUID, f
 	return endpoints, int64(count), nil
 }
 
-func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string, search string) ([]models.EndpointStats, int64, error) {
+// Search returns the endpoint traces matching the structured filters of the query
+func (e *endpointRepository) Search(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, search models.TraceSearchQuery, page, pageSize int, orderBy string, sortDirection string) ([]models.Endpoint, int64, error) {
+	searchSQL, searchArgs := traceSearchClause(search, "endpoint", true)
+	args := withFilterArgs([]interface{}{projectId, fromDate, toDate}, searchArgs)
+
+	var count uint64
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?"+searchSQL, args...).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+
+	allowedOrderBy := map[string]bool{
+		"recorded_at": true,
+		"duration":    true,
+		"status_code": true,
+		"body_size":   true,
+	}
+	if !allowedOrderBy[orderBy] {
+		orderBy = "recorded_at"
+	}
+	sortDir := "DESC"
+	if sortDirection == "asc" {
+		sortDir = "ASC"
+	}
+
+	query := "SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name, environment FROM endpoints WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?" + searchSQL + " ORDER BY " + orderBy + " " + sortDir + " LIMIT ? OFFSET ?"
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, pageSize, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var endpoints []models.Endpoint
+	for rows.Next() {
+		var t models.Endpoint
+		var attributesJSON string
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.Endpoint, &t.Duration, &t.RecordedAt, &t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment); err != nil {
+			return nil, 0, err
+		}
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &t.Attributes); err != nil {
+				t.Attributes = nil
+			}
+		}
+		endpoints = append(endpoints, t)
+	}
+
+	return endpoints, int64(count), nil
+}
+
+func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string, search string, apdexThresholdMs int, userIdentityAttribute string, filter models.TelemetryFilter) ([]models.EndpointStats, int64, error) {
 	// Build WHERE clause with optional search filter
 	// Count query uses bare column names; main query uses e. prefix for LEFT JOIN
 	whereClause := "project_id = ? AND recorded_at >= ? AND recorded_at <= ?"
@@ -98,6 +155,12 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 		args = append(args, search)
 	}
 
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+	joinFilterSQL, _ := traceFilterClause(filter, "e")
+	whereClause += filterSQL
+	joinWhereClause += joinFilterSQL
+	args = append(args, filterArgs...)
+
 	// Count unique endpoints
 	var count uint64
 	countQuery := "SELECT uniq(endpoint) FROM endpoints WHERE " + whereClause
@@ -110,13 +173,14 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 
 	// Map frontend field names to SQL expressions
 	orderByMap := map[string]string{
-		"count":        "count",
-		"p50_duration": "p50_duration",
-		"p95_duration": "p95_duration",
-		"p99_duration": "p99_duration",
-		"avg_duration": "avg_duration",
-		"last_seen":    "last_seen",
-		"impact":       "impact",
+		"count":          "count",
+		"p50_duration":   "p50_duration",
+		"p95_duration":   "p95_duration",
+		"p99_duration":   "p99_duration",
+		"avg_duration":   "avg_duration",
+		"last_seen":      "last_seen",
+		"impact":         "impact",
+		"affected_users": "affected_users",
 	}
 
 	orderExpr, ok := orderByMap[orderBy]
@@ -133,10 +197,10 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 	query := `SELECT
 		endpoint, total_count as count, p50_duration, p95_duration, p99_duration,
 		avg_duration, last_seen, offset_ms,
-		satisfied_count, tolerating_count, bad_count, client_error_count,
+		satisfied_count, tolerating_count, bad_count, client_error_count, affected_users,
 		greatest(
 			if(total_count > 0,
-				1.0 - ((satisfied_count + tolerating_count * 0.5) / total_count), 0.0),
+				1.0 - ` + apdexScoreFromCountsExpr("total_count") + `, 0.0),
 			multiIf(
 				bad_count / total_count > 0.33, 0.75,
 				bad_count / total_count > 0.20, 0.50,
@@ -171,20 +235,14 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 			quantile(0.99)(duration) as p99_duration,
 			avg(duration) as avg_duration,
 			max(recorded_at) as last_seen,
-			countIf(duration <= (750000000 + toInt64(offset_ms) * 1000000)
-				AND status_code < 500) as satisfied_count,
-			countIf(duration > (750000000 + toInt64(offset_ms) * 1000000)
-				AND duration <= (1500000000 + toInt64(offset_ms) * 1000000)
-				AND status_code < 500) as tolerating_count,
-			countIf(duration > (1500000000 + toInt64(offset_ms) * 1000000)
-				OR status_code >= 500) as bad_count,
-			countIf(status_code >= 400 AND status_code < 500) as client_error_count
+			` + apdexCountColumns(apdexThresholdMs) + `,
+			countIf(status_code >= 400 AND status_code < 500) as client_error_count,
+			uniq(user_id) as affected_users
 		FROM (
 			SELECT e.endpoint, e.duration, e.status_code, e.recorded_at,
-				   s.offset_ms as offset_ms
+				   s.offset_ms as offset_ms, ` + userIdentityExpr("e") + ` as user_id
 			FROM endpoints e
-			LEFT JOIN (SELECT * FROM slow_endpoints FINAL) AS s
-				ON e.endpoint = s.endpoint AND e.project_id = s.project_id
+			` + slowEndpointsJoin + `
 			WHERE ` + joinWhereClause + `
 		)
 		GROUP BY endpoint, offset_ms
@@ -192,8 +250,9 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 	ORDER BY ` + orderExpr + ` ` + sortDir + `
 	LIMIT ? OFFSET ?`
 
-	// Add pagination args
-	queryArgs := append(args, pageSize, offset)
+	// the user identity attribute comes first, it's selected before the WHERE clause
+	queryArgs := append([]interface{}{userIdentityAttribute}, args...)
+	queryArgs = append(queryArgs, pageSize, offset)
 
 	rows, err := (*chdb.Conn).Query(ctx, query, queryArgs...)
 	if err != nil {
@@ -208,7 +267,7 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 		var offsetMs uint32
 		var satisfiedCount, toleratingCount, badCount, clientErrorCount uint64
 		if err := rows.Scan(&s.Endpoint, &s.Count, &p50, &p95, &p99, &avg, &s.LastSeen,
-			&offsetMs, &satisfiedCount, &toleratingCount, &badCount, &clientErrorCount,
+			&offsetMs, &satisfiedCount, &toleratingCount, &badCount, &clientErrorCount, &s.AffectedUsers,
 			&s.Impact); err != nil {
 			return nil, 0, err
 		}
@@ -216,6 +275,7 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 		s.P95Duration = time.Duration(p95)
 		s.P99Duration = time.Duration(p99)
 		s.AvgDuration = time.Duration(avg)
+		s.Apdex = apdexScore(s.Count, satisfiedCount, toleratingCount)
 		s.ImpactReason = computeImpactReason(s.Count, satisfiedCount, toleratingCount, badCount, clientErrorCount, p99, offsetMs)
 		stats = append(stats, s)
 	}
@@ -223,9 +283,12 @@ func (e *endpointRepository) FindGroupedByEndpoint(ctx context.Context, projectI
 	return stats, int64(count), nil
 }
 
-func (e *endpointRepository) FindByEndpoint(ctx context.Context, projectId uuid.UUID, endpoint string, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string) ([]models.Endpoint, int64, error) {
+func (e *endpointRepository) FindByEndpoint(ctx context.Context, projectId uuid.UUID, endpoint string, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string, filter models.TelemetryFilter) ([]models.Endpoint, int64, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+	args := withFilterArgs([]interface{}{projectId, endpoint, fromDate, toDate}, filterArgs)
+
 	var count uint64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM endpoints WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, endpoint, fromDate, toDate).Scan(&count)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM endpoints WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, args...).Scan(&count)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -249,8 +312,8 @@ func (e *endpointRepository) FindByEndpoint(ctx context.Context, projectId uuid.
 		sortDir = "ASC"
 	}
 
-	query := "SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name FROM endpoints WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ? ORDER BY " + orderBy + " " + sortDir + " LIMIT ? OFFSET ?"
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, endpoint, fromDate, toDate, pageSize, offset)
+	query := "SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name, environment FROM endpoints WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ?" + filterSQL + " ORDER BY " + orderBy + " " + sortDir + " LIMIT ? OFFSET ?"
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -260,7 +323,7 @@ func (e *endpointRepository) FindByEndpoint(ctx context.Context, projectId uuid.
 	for rows.Next() {
 		var t models.Endpoint
 		var attributesJSON string
-		if err := rows.Scan(&t.Id, &t.ProjectId, &t.Endpoint, &t.Duration, &t.RecordedAt, &t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName); err != nil {
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.Endpoint, &t.Duration, &t.RecordedAt, &t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment); err != nil {
 			return nil, 0, err
 		}
 		if attributesJSON != "" && attributesJSON != "{}" {
@@ -276,7 +339,7 @@ func (e *endpointRepository) FindByEndpoint(ctx context.Context, projectId uuid.
 
 // FindById returns a single endpoint by ID
 func (e *endpointRepository) FindById(ctx context.Context, projectId, endpointId uuid.UUID) (*models.Endpoint, error) {
-	query := `SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name
+	query := `SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name, environment, resource_attributes
 		FROM endpoints
 		WHERE project_id = ? AND id = ?
 		LIMIT 1`
@@ -286,7 +349,7 @@ func (e *endpointRepository) FindById(ctx context.Context, projectId, endpointId
 
 	err := (*chdb.Conn).QueryRow(ctx, query, projectId, endpointId).Scan(
 		&t.Id, &t.ProjectId, &t.Endpoint, &t.Duration, &t.RecordedAt,
-		&t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName)
+		&t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment, &t.ResourceAttributes)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -304,6 +367,42 @@ func (e *endpointRepository) FindById(ctx context.Context, projectId, endpointId
 	return &t, nil
 }
 
+// FindByTraceIdInProjects returns the endpoints recorded for a trace id in any of the projects,
+// a distributed trace has one root endpoint per service that reported it
+func (e *endpointRepository) FindByTraceIdInProjects(ctx context.Context, projectIds []uuid.UUID, traceId uuid.UUID) ([]models.Endpoint, error) {
+	if len(projectIds) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id, project_id, endpoint, duration, recorded_at, status_code, body_size, client_ip, attributes, app_version, server_name, environment, resource_attributes
+		FROM endpoints
+		WHERE project_id IN (?) AND id = ?
+		ORDER BY recorded_at ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, projectIds, traceId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []models.Endpoint
+	for rows.Next() {
+		var t models.Endpoint
+		var attributesJSON string
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.Endpoint, &t.Duration, &t.RecordedAt, &t.StatusCode, &t.BodySize, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment, &t.ResourceAttributes); err != nil {
+			return nil, err
+		}
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &t.Attributes); err != nil {
+				t.Attributes = nil
+			}
+		}
+		endpoints = append(endpoints, t)
+	}
+
+	return endpoints, nil
+}
+
 // CountByHour returns endpoint counts grouped by hour
 func (e *endpointRepository) CountByHour(ctx context.Context, projectId uuid.UUID, start, end time.Time) ([]models.TimeSeriesPoint, error) {
 	query := `SELECT
@@ -389,16 +488,18 @@ func (e *endpointRepository) ErrorRateByHour(ctx context.Context, projectId uuid
 }
 
 // CountByInterval returns endpoint counts grouped by configurable interval in minutes
-func (e *endpointRepository) CountByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int) ([]models.TimeSeriesPoint, error) {
+func (e *endpointRepository) CountByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.TimeSeriesPoint, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
 	query := `SELECT
 		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
 		toFloat64(count()) as count
 	FROM endpoints
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY bucket
 	ORDER BY bucket ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, intervalMinutes, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -417,16 +518,18 @@ func (e *endpointRepository) CountByInterval(ctx context.Context, projectId uuid
 }
 
 // AvgDurationByInterval returns average response time in ms grouped by configurable interval
-func (e *endpointRepository) AvgDurationByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int) ([]models.TimeSeriesPoint, error) {
+func (e *endpointRepository) AvgDurationByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.TimeSeriesPoint, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
 	query := `SELECT
 		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
 		avg(duration) / 1000000 as avg_duration_ms
 	FROM endpoints
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY bucket
 	ORDER BY bucket ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, intervalMinutes, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -444,17 +547,54 @@ func (e *endpointRepository) AvgDurationByInterval(ctx context.Context, projectI
 	return points, nil
 }
 
+// PerformanceByInterval returns the percentiles, throughput and error rate of a single endpoint grouped by configurable interval
+func (e *endpointRepository) PerformanceByInterval(ctx context.Context, projectId uuid.UUID, endpoint string, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.PerformanceSeriesPoint, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
+	query := `SELECT
+		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
+		count() as count,
+		quantile(0.5)(duration) / 1000000 as p50_duration_ms,
+		quantile(0.95)(duration) / 1000000 as p95_duration_ms,
+		quantile(0.99)(duration) / 1000000 as p99_duration_ms,
+		countIf(status_code >= 500) * 100.0 / count() as error_rate
+	FROM endpoints
+	WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
+	GROUP BY bucket
+	ORDER BY bucket ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, endpoint, start, end}, filterArgs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []models.PerformanceSeriesPoint
+	for rows.Next() {
+		var p models.PerformanceSeriesPoint
+		if err := rows.Scan(&p.Timestamp, &p.Count, &p.P50Duration, &p.P95Duration, &p.P99Duration, &p.ErrorRate); err != nil {
+			return nil, err
+		}
+		p.Throughput = float64(p.Count) / float64(intervalMinutes)
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
 // ErrorRateByInterval returns error rate (percentage) grouped by configurable interval
-func (e *endpointRepository) ErrorRateByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int) ([]models.TimeSeriesPoint, error) {
+func (e *endpointRepository) ErrorRateByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.TimeSeriesPoint, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
 	query := `SELECT
 		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
 		countIf(status_code >= 500) * 100.0 / count() as error_rate
 	FROM endpoints
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY bucket
 	ORDER BY bucket ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, intervalMinutes, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -472,14 +612,16 @@ func (e *endpointRepository) ErrorRateByInterval(ctx context.Context, projectId
 	return points, nil
 }
 
-func (e *endpointRepository) FindWorstEndpoints(ctx context.Context, projectId uuid.UUID, start, end time.Time, limit int) ([]models.EndpointStats, error) {
+func (e *endpointRepository) FindWorstEndpoints(ctx context.Context, projectId uuid.UUID, start, end time.Time, limit int, apdexThresholdMs int, filter models.TelemetryFilter) ([]models.EndpointStats, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "e")
+
 	query := `SELECT
 		endpoint, total_count, p50_duration, p95_duration, p99_duration,
 		avg_duration, last_seen, offset_ms,
 		satisfied_count, tolerating_count, bad_count, client_error_count,
 		greatest(
 			if(total_count > 0,
-				1.0 - ((satisfied_count + tolerating_count * 0.5) / total_count), 0.0),
+				1.0 - ` + apdexScoreFromCountsExpr("total_count") + `, 0.0),
 			multiIf(
 				bad_count / total_count > 0.33, 0.75,
 				bad_count / total_count > 0.20, 0.50,
@@ -514,28 +656,22 @@ func (e *endpointRepository) FindWorstEndpoints(ctx context.Context, projectId u
 			quantile(0.99)(duration) as p99_duration,
 			avg(duration) as avg_duration,
 			max(recorded_at) as last_seen,
-			countIf(duration <= (750000000 + toInt64(offset_ms) * 1000000)
-				AND status_code < 500) as satisfied_count,
-			countIf(duration > (750000000 + toInt64(offset_ms) * 1000000)
-				AND duration <= (1500000000 + toInt64(offset_ms) * 1000000)
-				AND status_code < 500) as tolerating_count,
-			countIf(duration > (1500000000 + toInt64(offset_ms) * 1000000)
-				OR status_code >= 500) as bad_count,
+			` + apdexCountColumns(apdexThresholdMs) + `,
 			countIf(status_code >= 400 AND status_code < 500) as client_error_count
 		FROM (
 			SELECT e.endpoint, e.duration, e.status_code, e.recorded_at,
 				   s.offset_ms as offset_ms
 			FROM endpoints e
-			LEFT JOIN (SELECT * FROM slow_endpoints FINAL) AS s
-				ON e.endpoint = s.endpoint AND e.project_id = s.project_id
-			WHERE e.project_id = ? AND e.recorded_at >= ? AND e.recorded_at <= ?
+			` + slowEndpointsJoin + `
+			WHERE e.project_id = ? AND e.recorded_at >= ? AND e.recorded_at <= ?` + filterSQL + `
 		)
 		GROUP BY endpoint, offset_ms
 	)
 	ORDER BY impact DESC
 	LIMIT ?`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, start, end, limit)
+	args := withFilterArgs([]interface{}{projectId, start, end}, filterArgs)
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, limit)...)
 	if err != nil {
 		return nil, err
 	}
@@ -556,6 +692,7 @@ func (e *endpointRepository) FindWorstEndpoints(ctx context.Context, projectId u
 		s.P95Duration = time.Duration(p95)
 		s.P99Duration = time.Duration(p99)
 		s.AvgDuration = time.Duration(avg)
+		s.Apdex = apdexScore(s.Count, satisfiedCount, toleratingCount)
 		s.ImpactReason = computeImpactReason(s.Count, satisfiedCount, toleratingCount, badCount, clientErrorCount, p99, offsetMs)
 		stats = append(stats, s)
 	}
@@ -564,13 +701,15 @@ func (e *endpointRepository) FindWorstEndpoints(ctx context.Context, projectId u
 }
 
 // GetEndpointStats returns aggregate statistics for a specific endpoint
-func (e *endpointRepository) GetEndpointStats(ctx context.Context, projectId uuid.UUID, endpoint string, start, end time.Time) (*models.EndpointDetailStats, error) {
+func (e *endpointRepository) GetEndpointStats(ctx context.Context, projectId uuid.UUID, endpoint string, start, end time.Time, apdexThresholdMs int, filter models.TelemetryFilter) (*models.EndpointDetailStats, error) {
 	// Calculate time range duration for throughput calculation
 	durationMinutes := end.Sub(start).Minutes()
 	if durationMinutes < 1 {
 		durationMinutes = 1
 	}
 
+	filterSQL, filterArgs := traceFilterClause(filter, "e")
+
 	query := `SELECT
 		count() as count,
 		if(count() > 0, avg(duration) / 1000000, 0) as avg_duration_ms,
@@ -578,77 +717,206 @@ func (e *endpointRepository) GetEndpointStats(ctx context.Context, projectId uui
 		if(count() > 0, quantile(0.95)(duration) / 1000000, 0) as p95_duration_ms,
 		if(count() > 0, quantile(0.99)(duration) / 1000000, 0) as p99_duration_ms,
 		if(count() > 0, countIf(status_code >= 500) * 100.0 / count(), 0) as error_rate,
-		if(count() > 0,
-			countIf(duration <= 500000000 AND status_code < 500) +
-			(countIf(duration > 500000000 AND duration <= 2000000000 AND status_code < 500) * 0.5),
-			0) as satisfied_tolerating
-	FROM endpoints
-	WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ?`
+		` + apdexScoreExpr(apdexThresholdMs) + ` as apdex,
+		any(offset_ms) as offset_ms
+	FROM (
+		SELECT e.duration, e.status_code, s.offset_ms as offset_ms
+		FROM endpoints e
+		` + slowEndpointsJoin + `
+		WHERE e.project_id = ? AND e.endpoint = ? AND e.recorded_at >= ? AND e.recorded_at <= ?` + filterSQL + `
+	)`
 
 	var stats models.EndpointDetailStats
 	var count uint64
-	var satisfiedTolerating float64
+	var offsetMs uint32
 
-	err := (*chdb.Conn).QueryRow(ctx, query, projectId, endpoint, start, end).Scan(
+	err := (*chdb.Conn).QueryRow(ctx, query, withFilterArgs([]interface{}{projectId, endpoint, start, end}, filterArgs)...).Scan(
 		&count,
 		&stats.AvgDuration,
 		&stats.MedianDuration,
 		&stats.P95Duration,
 		&stats.P99Duration,
 		&stats.ErrorRate,
-		&satisfiedTolerating,
+		&stats.Apdex,
+		&offsetMs,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	stats.Count = int64(count)
-	// Calculate Apdex: (satisfied + tolerating*0.5) / total
-	if count > 0 {
-		stats.Apdex = satisfiedTolerating / float64(count)
-	}
+	stats.ApdexThresholdMs = apdexThresholdMs + int(offsetMs)
 	// Calculate throughput (requests per minute)
 	stats.Throughput = float64(count) / durationMinutes
 
 	return &stats, nil
 }
 
+// GetSpanBreakdown aggregates the spans of the endpoint's traces by name. Self time is a span's duration minus
+// the duration of its direct children, clamped at zero when children run in parallel. Only the 50 names with the
+// most self time are listed, the uninstrumented share accounts for all of them.
+func (e *endpointRepository) GetSpanBreakdown(ctx context.Context, projectId uuid.UUID, endpoint string, start, end time.Time, filter models.TelemetryFilter) (*models.SpanBreakdown, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+	endpointArgs := withFilterArgs([]interface{}{projectId, endpoint, start, end}, filterArgs)
+
+	var breakdown models.SpanBreakdown
+	var count uint64
+	var totalDuration int64
+	err := (*chdb.Conn).QueryRow(ctx, `SELECT count(), sum(duration), if(count() > 0, avg(duration) / 1000000, 0)
+	FROM endpoints
+	WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ?`+filterSQL, endpointArgs...).Scan(&count, &totalDuration, &breakdown.AvgDuration)
+	if err != nil {
+		return nil, err
+	}
+	breakdown.RequestCount = int64(count)
+	breakdown.Spans = []models.SpanBreakdownRow{}
+	if count == 0 {
+		return &breakdown, nil
+	}
+
+	// spans are recorded slightly after the request that started them
+	spanEnd := end.Add(time.Hour)
+	traceIds := `SELECT id FROM endpoints WHERE project_id = ? AND endpoint = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL
+
+	query := `SELECT
+		name,
+		count() as span_count,
+		avg(self_time) / 1000000,
+		quantile(0.95)(self_time) / 1000000,
+		sum(self_time) as total_self_time,
+		sum(total_self_time) OVER () as all_self_time
+	FROM (
+		SELECT s.name as name, greatest(s.duration - c.children_duration, 0) as self_time
+		FROM spans s
+		LEFT JOIN (
+			SELECT trace_id, assumeNotNull(parent_id) as parent_id, sum(duration) as children_duration
+			FROM spans
+			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND parent_id IS NOT NULL AND trace_id IN (` + traceIds + `)
+			GROUP BY trace_id, parent_id
+		) c ON s.trace_id = c.trace_id AND s.id = c.parent_id
+		WHERE s.project_id = ? AND s.recorded_at >= ? AND s.recorded_at <= ? AND s.trace_id IN (` + traceIds + `)
+	)
+	GROUP BY name
+	ORDER BY total_self_time DESC
+	LIMIT 50`
+
+	args := []interface{}{projectId, start, spanEnd}
+	args = append(args, endpointArgs...)
+	args = append(args, projectId, start, spanEnd)
+	args = append(args, endpointArgs...)
+
+	rows, err := (*chdb.Conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// spans running in parallel can add up to more than the requests they belong to
+	timeShare := func(selfTime int64) float64 {
+		if totalDuration <= 0 {
+			return 0
+		}
+		return min(float64(selfTime)/float64(totalDuration)*100, 100)
+	}
+
+	var allSelfTime int64
+	for rows.Next() {
+		var row models.SpanBreakdownRow
+		var spanCount uint64
+		var totalSelfTime int64
+		if err := rows.Scan(&row.Name, &spanCount, &row.AvgSelfTime, &row.P95SelfTime, &totalSelfTime, &allSelfTime); err != nil {
+			return nil, err
+		}
+		row.AvgCountPerRequest = float64(spanCount) / float64(count)
+		row.TimeShare = timeShare(totalSelfTime)
+		breakdown.Spans = append(breakdown.Spans, row)
+	}
+	breakdown.UninstrumentedShare = 100 - timeShare(allSelfTime)
+
+	return &breakdown, nil
+}
+
+// ApdexByInterval returns the Apdex score (0-1) grouped by configurable interval.
+// When endpoint is empty the score covers all endpoints, each measured against its own effective T.
+func (e *endpointRepository) ApdexByInterval(ctx context.Context, projectId uuid.UUID, endpoint string, start, end time.Time, intervalMinutes int, apdexThresholdMs int, filter models.TelemetryFilter) ([]models.TimeSeriesPoint, error) {
+	whereClause := "e.project_id = ? AND e.recorded_at >= ? AND e.recorded_at <= ?"
+	args := []interface{}{intervalMinutes, projectId, start, end}
+	if endpoint != "" {
+		whereClause += " AND e.endpoint = ?"
+		args = append(args, endpoint)
+	}
+	filterSQL, filterArgs := traceFilterClause(filter, "e")
+	whereClause += filterSQL
+	args = append(args, filterArgs...)
+
+	query := `SELECT
+		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
+		toFloat64(` + apdexScoreExpr(apdexThresholdMs) + `) as apdex
+	FROM (
+		SELECT e.duration, e.status_code, e.recorded_at, s.offset_ms as offset_ms
+		FROM endpoints e
+		` + slowEndpointsJoin + `
+		WHERE ` + whereClause + `
+	)
+	GROUP BY bucket
+	ORDER BY bucket ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []models.TimeSeriesPoint
+	for rows.Next() {
+		var p models.TimeSeriesPoint
+		if err := rows.Scan(&p.Timestamp, &p.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
 // GetEndpointStackedChart returns time-bucketed data for top 5 endpoints by metric + "Other"
-func (e *endpointRepository) GetEndpointStackedChart(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, metricType string) (*models.EndpointStackedChartResponse, error) {
+func (e *endpointRepository) GetEndpointStackedChart(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, metricType string, filter models.TelemetryFilter) (*models.EndpointStackedChartResponse, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
 	// Step 1: Get top 5 endpoints ranked by selected metric
 	var rankQuery string
 	switch metricType {
 	case "total_time":
 		rankQuery = `SELECT endpoint, count() * quantile(0.5)(duration) / 1000000 as metric_value
 			FROM endpoints
-			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 			GROUP BY endpoint
 			ORDER BY metric_value DESC
 			LIMIT 5`
 	case "p95":
 		rankQuery = `SELECT endpoint, quantile(0.95)(duration) / 1000000 as metric_value
 			FROM endpoints
-			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 			GROUP BY endpoint
 			ORDER BY metric_value DESC
 			LIMIT 5`
 	case "p99":
 		rankQuery = `SELECT endpoint, quantile(0.99)(duration) / 1000000 as metric_value
 			FROM endpoints
-			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 			GROUP BY endpoint
 			ORDER BY metric_value DESC
 			LIMIT 5`
 	default: // p50
 		rankQuery = `SELECT endpoint, quantile(0.5)(duration) / 1000000 as metric_value
 			FROM endpoints
-			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+			WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 			GROUP BY endpoint
 			ORDER BY metric_value DESC
 			LIMIT 5`
 	}
 
-	rows, err := (*chdb.Conn).Query(ctx, rankQuery, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, rankQuery, withFilterArgs([]interface{}{projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -698,7 +966,7 @@ func (e *endpointRepository) GetEndpointStackedChart(ctx context.Context, projec
 		` + caseExpr + ` as endpoint_category,
 		` + metricExpr + ` as metric_value
 	FROM endpoints
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY bucket, endpoint_category
 	ORDER BY bucket ASC, endpoint_category ASC`
 
@@ -709,6 +977,7 @@ func (e *endpointRepository) GetEndpointStackedChart(ctx context.Context, projec
 		args = append(args, ep)
 	}
 	args = append(args, projectId, start, end)
+	args = append(args, filterArgs...)
 
 	rows, err = (*chdb.Conn).Query(ctx, timeSeriesQuery, args...)
 	if err != nil {
@@ -743,6 +1012,130 @@ func (e *endpointRepository) GetEndpointStackedChart(ctx context.Context, projec
 	}, nil
 }
 
+// GetStatsForVersions returns per-endpoint stats for each of the given app versions
+func (e *endpointRepository) GetStatsForVersions(ctx context.Context, projectId uuid.UUID, versions []string, start, end time.Time, filter models.TelemetryFilter) ([]models.VersionEndpointStats, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
+	query := `SELECT
+		app_version,
+		endpoint,
+		count() as count,
+		quantile(0.5)(duration) as p50_duration,
+		quantile(0.95)(duration) as p95_duration,
+		quantile(0.99)(duration) as p99_duration,
+		countIf(status_code >= 500) * 100.0 / count() as error_rate
+	FROM endpoints
+	WHERE project_id = ? AND app_version IN (?) AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
+	GROUP BY app_version, endpoint`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{projectId, versions, start, end}, filterArgs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []models.VersionEndpointStats
+	for rows.Next() {
+		var s models.VersionEndpointStats
+		var p50, p95, p99 float64
+		if err := rows.Scan(&s.AppVersion, &s.Endpoint, &s.Count, &p50, &p95, &p99, &s.ErrorRate); err != nil {
+			return nil, err
+		}
+		s.P50Duration = time.Duration(p50)
+		s.P95Duration = time.Duration(p95)
+		s.P99Duration = time.Duration(p99)
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+// sloGoodExpr returns the condition marking an endpoint row as a good event for the SLO
+func sloGoodExpr(slo *models.Slo) string {
+	if slo.SloType == models.SloTypeLatency {
+		return fmt.Sprintf("duration <= %d", int64(slo.LatencyThresholdMs)*1_000_000)
+	}
+	return "status_code < 500"
+}
+
+// CountSloEvents returns good and total event counts for each of the given windows ending at end
+func (e *endpointRepository) CountSloEvents(ctx context.Context, projectId uuid.UUID, slo *models.Slo, end time.Time, windows []time.Duration) ([]models.SloCounts, error) {
+	if len(windows) == 0 {
+		return nil, nil
+	}
+
+	goodExpr := sloGoodExpr(slo)
+	columns := ""
+	args := []interface{}{}
+	longest := windows[0]
+	for i, w := range windows {
+		if i > 0 {
+			columns += ", "
+		}
+		columns += "countIf(" + goodExpr + " AND recorded_at >= ?), countIf(recorded_at >= ?)"
+		args = append(args, end.Add(-w), end.Add(-w))
+		if w > longest {
+			longest = w
+		}
+	}
+
+	whereClause := "project_id = ? AND recorded_at >= ? AND recorded_at <= ?"
+	args = append(args, projectId, end.Add(-longest), end)
+	if slo.Endpoint != "" {
+		whereClause += " AND endpoint = ?"
+		args = append(args, slo.Endpoint)
+	}
+
+	query := "SELECT " + columns + " FROM endpoints WHERE " + whereClause
+
+	counts := make([]models.SloCounts, len(windows))
+	dest := make([]interface{}, 0, len(windows)*2)
+	for i := range counts {
+		dest = append(dest, &counts[i].Good, &counts[i].Total)
+	}
+	if err := (*chdb.Conn).QueryRow(ctx, query, args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// SloEventsByInterval returns good and total event counts grouped by configurable interval
+func (e *endpointRepository) SloEventsByInterval(ctx context.Context, projectId uuid.UUID, slo *models.Slo, start, end time.Time, intervalMinutes int) ([]models.SloHistoryPoint, error) {
+	whereClause := "project_id = ? AND recorded_at >= ? AND recorded_at <= ?"
+	args := []interface{}{intervalMinutes, projectId, start, end}
+	if slo.Endpoint != "" {
+		whereClause += " AND endpoint = ?"
+		args = append(args, slo.Endpoint)
+	}
+
+	query := `SELECT
+		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
+		countIf(` + sloGoodExpr(slo) + `) as good,
+		count() as total
+	FROM endpoints
+	WHERE ` + whereClause + `
+	GROUP BY bucket
+	ORDER BY bucket ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []models.SloHistoryPoint
+	for rows.Next() {
+		var p models.SloHistoryPoint
+		if err := rows.Scan(&p.Timestamp, &p.Good, &p.Total); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
 func (e *endpointRepository) GetSlowEndpoint(ctx context.Context, projectId uuid.UUID, endpoint string) (uint32, string, error) {
 	var offsetMs uint32
 	var reason string
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"backend/app/chdb"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type environmentRepository struct{}
+
+// FindDistinct returns the environments that reported telemetry in the range, most active first
+func (r *environmentRepository) FindDistinct(ctx context.Context, projectId uuid.UUID, start, end time.Time) ([]string, error) {
+	query := `SELECT environment, sum(c) as total
+	FROM (
+		SELECT environment, count() as c FROM endpoints
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND environment != ''
+		GROUP BY environment
+		UNION ALL
+		SELECT environment, count() as c FROM tasks
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND environment != ''
+		GROUP BY environment
+		UNION ALL
+		SELECT environment, count() as c FROM exception_stack_traces
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND environment != ''
+		GROUP BY environment
+		UNION ALL
+		SELECT environment, count() as c FROM metric_records
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND environment != ''
+		GROUP BY environment
+	)
+	GROUP BY environment
+	ORDER BY total DESC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query,
+		projectId, start, end,
+		projectId, start, end,
+		projectId, start, end,
+		projectId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var environments []string
+	for rows.Next() {
+		var environment string
+		var total uint64
+		if err := rows.Scan(&environment, &total); err != nil {
+			return nil, err
+		}
+		environments = append(environments, environment)
+	}
+
+	return environments, nil
+}
+
+var EnvironmentRepository = environmentRepository{}
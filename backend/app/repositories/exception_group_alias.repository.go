@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"backend/app/models"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tracewayapp/go-lightning/lit"
+)
+
+type exceptionGroupAliasRepository struct{}
+
+// FindByProject returns the merged exception hashes of a project mapped to the canonical hash of their group
+func (r *exceptionGroupAliasRepository) FindByProject(tx *sql.Tx, projectId uuid.UUID) (map[string]string, error) {
+	rows, err := lit.Select[models.ExceptionGroupAlias](
+		tx,
+		`SELECT id, project_id, exception_hash, canonical_hash, created_at
+		FROM exception_group_aliases
+		WHERE project_id = $1`,
+		projectId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make(map[string]string, len(rows))
+	for _, row := range rows {
+		aliases[row.ExceptionHash] = row.CanonicalHash
+	}
+	return aliases, nil
+}
+
+// Save points each exception hash at its canonical hash, replacing the group it was merged into before
+func (r *exceptionGroupAliasRepository) Save(tx *sql.Tx, projectId uuid.UUID, aliases map[string]string) error {
+	now := time.Now().UTC()
+	for hash, canonical := range aliases {
+		if err := r.Delete(tx, projectId, hash); err != nil {
+			return err
+		}
+		alias := &models.ExceptionGroupAlias{
+			ProjectId:     projectId,
+			ExceptionHash: hash,
+			CanonicalHash: canonical,
+			CreatedAt:     now,
+		}
+		if _, err := lit.Insert(tx, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *exceptionGroupAliasRepository) Delete(tx *sql.Tx, projectId uuid.UUID, hash string) error {
+	return lit.Delete(tx, "DELETE FROM exception_group_aliases WHERE project_id = $1 AND exception_hash = $2", projectId, hash)
+}
+
+var ExceptionGroupAliasRepository = exceptionGroupAliasRepository{}
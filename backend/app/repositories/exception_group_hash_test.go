@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExceptionGroupHashExpr(t *testing.T) {
+	tests := []struct {
+		name       string
+		column     string
+		aliases    map[string]string
+		expectExpr string
+		expectArgs []interface{}
+	}{
+		{
+			name:       "no merged hashes",
+			column:     "exception_hash",
+			expectExpr: "exception_hash",
+		},
+		{
+			name:       "merged hashes map to their group",
+			column:     "e.exception_hash",
+			aliases:    map[string]string{"c": "a", "b": "a", "e": "d"},
+			expectExpr: "transform(e.exception_hash, ?, ?, e.exception_hash)",
+			expectArgs: []interface{}{[]string{"b", "c", "e"}, []string{"a", "a", "d"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, args := exceptionGroupHashExpr(tt.column, tt.aliases)
+			if expr != tt.expectExpr {
+				t.Errorf("expr = %q, want %q", expr, tt.expectExpr)
+			}
+			if !reflect.DeepEqual(args, tt.expectArgs) {
+				t.Errorf("args = %v, want %v", args, tt.expectArgs)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,7 +18,7 @@ import (
 type exceptionStackTraceRepository struct{}
 
 func (e *exceptionStackTraceRepository) InsertAsync(ctx context.Context, lines []models.ExceptionStackTrace) error {
-	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO exception_stack_traces (id, project_id, trace_id, trace_type, exception_hash, stack_trace, recorded_at, attributes, app_version, server_name, is_message)")
+	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO exception_stack_traces (id, project_id, trace_id, trace_type, exception_hash, stack_trace, raw_stack_trace, debug_ids, recorded_at, attributes, app_version, server_name, environment, is_message, exception_type, exception_message, frames)")
 	if err != nil {
 		return err
 	}
@@ -32,24 +33,35 @@ func (e *exceptionStackTraceRepository) InsertAsync(ctx context.Context, lines [
 		if est.IsMessage {
 			isMessage = 1
 		}
+		framesJSON := "[]"
+		if len(est.Frames) != 0 {
+			if framesBytes, err := json.Marshal(est.Frames); err == nil {
+				framesJSON = string(framesBytes)
+			}
+		}
+		debugIds := est.DebugIds
+		if debugIds == nil {
+			debugIds = map[string]string{}
+		}
 		traceType := est.TraceType
 		if traceType == "" {
 			traceType = "endpoint"
 		}
-		if err := batch.Append(est.Id, est.ProjectId, est.TraceId, traceType, est.ExceptionHash, est.StackTrace, est.RecordedAt, attributesJSON, est.AppVersion, est.ServerName, isMessage); err != nil {
+		if err := batch.Append(est.Id, est.ProjectId, est.TraceId, traceType, est.ExceptionHash, est.StackTrace, est.RawStackTrace, debugIds, est.RecordedAt, attributesJSON, est.AppVersion, est.ServerName, est.Environment, isMessage, est.ExceptionType, est.ExceptionMessage, framesJSON); err != nil {
 			return err
 		}
 	}
 	return batch.Send()
 }
 
-func (e *exceptionStackTraceRepository) CountBetween(ctx context.Context, projectId uuid.UUID, start, end time.Time) (int64, error) {
+func (e *exceptionStackTraceRepository) CountBetween(ctx context.Context, projectId uuid.UUID, start, end time.Time, filter models.TelemetryFilter) (int64, error) {
+	filterSQL, filterArgs := filterClause(filter, "")
 	var count uint64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM exception_stack_traces WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, start, end).Scan(&count)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM exception_stack_traces WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, withFilterArgs([]interface{}{projectId, start, end}, filterArgs)...).Scan(&count)
 	return int64(count), err
 }
 
-func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, search string, searchType string, includeArchived bool) ([]models.ExceptionGroup, int64, error) {
+func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, search string, searchType string, exceptionType string, includeArchived bool, aliases map[string]string, userIdentityAttribute string, filter models.TelemetryFilter) ([]models.ExceptionGroup, int64, error) {
 	offset := (page - 1) * pageSize
 
 	sortDirection := "DESC"
@@ -59,9 +71,10 @@ func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, project
 	}
 
 	allowedOrderBy := map[string]bool{
-		"last_seen":  true,
-		"first_seen": true,
-		"count":      true,
+		"last_seen":      true,
+		"first_seen":     true,
+		"count":          true,
+		"affected_users": true,
 	}
 
 	if !allowedOrderBy[orderBy] {
@@ -85,6 +98,15 @@ func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, project
 	}
 	// "all" or empty = no filter
 
+	if exceptionType != "" {
+		whereClause += " AND e.exception_type = ?"
+		args = append(args, exceptionType)
+	}
+
+	filterSQL, filterArgs := filterClause(filter, "e")
+	whereClause += filterSQL
+	args = append(args, filterArgs...)
+
 	// Build HAVING clause for archive filtering
 	// Show exceptions if: not archived OR last occurrence is after archive time
 	havingClause := ""
@@ -92,24 +114,28 @@ func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, project
 		havingClause = " HAVING any(a.archived_at) IS NULL OR max(e.recorded_at) > any(a.archived_at)"
 	}
 
+	// Merged exception hashes are grouped and archived under the canonical hash of their group
+	groupHash, groupArgs := exceptionGroupHashExpr("e.exception_hash", aliases)
+
 	// Subquery to get max archived_at per exception hash
 	archiveSubquery := `LEFT JOIN (
 		SELECT exception_hash, max(archived_at) as archived_at
 		FROM archived_exceptions FINAL
 		WHERE project_id = ?
 		GROUP BY exception_hash
-	) a ON e.exception_hash = a.exception_hash`
+	) a ON ` + groupHash + ` = a.exception_hash`
+	archiveArgs := append([]interface{}{projectId}, groupArgs...)
 
 	// Count query needs to wrap the grouped query to apply HAVING filter correctly
 	countQuery := `SELECT count() FROM (
-		SELECT e.exception_hash
+		SELECT ` + groupHash + ` as group_hash
 		FROM exception_stack_traces e
 		` + archiveSubquery + `
 		WHERE ` + whereClause + `
-		GROUP BY e.exception_hash` + havingClause + `
+		GROUP BY group_hash` + havingClause + `
 	)`
 
-	countArgs := append([]interface{}{projectId}, args...)
+	countArgs := append(append(append([]interface{}{}, groupArgs...), archiveArgs...), args...)
 	var count uint64
 	err := (*chdb.Conn).QueryRow(ctx, countQuery, countArgs...).Scan(&count)
 	if err != nil {
@@ -117,14 +143,18 @@ func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, project
 	}
 
 	// Main query with archive-aware filtering
-	fullQuery := `SELECT e.exception_hash, any(e.stack_trace), max(e.recorded_at) as last_seen, min(e.recorded_at) as first_seen, count() as count
+	fullQuery := `SELECT ` + groupHash + ` as group_hash, any(e.stack_trace), max(e.recorded_at) as last_seen, min(e.recorded_at) as first_seen, count() as count,
+		argMinIf(e.app_version, e.recorded_at, e.app_version != '') as first_seen_release,
+		uniq(` + userIdentityExpr("e") + `) as affected_users,
+		argMax(e.exception_type, e.recorded_at) as exception_type
 		FROM exception_stack_traces e
 		` + archiveSubquery + `
 		WHERE ` + whereClause + `
-		GROUP BY e.exception_hash` + havingClause + `
+		GROUP BY group_hash` + havingClause + `
 		ORDER BY ` + orderBy + ` ` + sortDirection + ` LIMIT ? OFFSET ?`
 
-	queryArgs := append([]interface{}{projectId}, args...)
+	queryArgs := append(append([]interface{}{}, groupArgs...), userIdentityAttribute)
+	queryArgs = append(append(queryArgs, archiveArgs...), args...)
 	queryArgs = append(queryArgs, pageSize, offset)
 	rows, err := (*chdb.Conn).Query(ctx, fullQuery, queryArgs...)
 	if err != nil {
@@ -135,7 +165,7 @@ func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, project
 	var groups []models.ExceptionGroup
 	for rows.Next() {
 		var g models.ExceptionGroup
-		if err := rows.Scan(&g.ExceptionHash, &g.StackTrace, &g.LastSeen, &g.FirstSeen, &g.Count); err != nil {
+		if err := rows.Scan(&g.ExceptionHash, &g.StackTrace, &g.LastSeen, &g.FirstSeen, &g.Count, &g.FirstSeenRelease, &g.AffectedUsers, &g.ExceptionType); err != nil {
 			return nil, 0, err
 		}
 		groups = append(groups, g)
@@ -144,14 +174,19 @@ func (e *exceptionStackTraceRepository) FindGrouped(ctx context.Context, project
 	return groups, int64(count), nil
 }
 
-func (e *exceptionStackTraceRepository) FindByHash(ctx context.Context, projectId uuid.UUID, exceptionHash string, page, pageSize int) (*models.ExceptionGroup, []models.ExceptionStackTrace, int64, error) {
+// FindByHash returns a group and its occurrences, including the occurrences of the hashes merged into it
+func (e *exceptionStackTraceRepository) FindByHash(ctx context.Context, projectId uuid.UUID, exceptionHash string, aliases map[string]string, page, pageSize int, filter models.TelemetryFilter) (*models.ExceptionGroup, []models.ExceptionStackTrace, int64, error) {
 	offset := (page - 1) * pageSize
+	filterSQL, filterArgs := filterClause(filter, "")
+	groupHash, groupArgs := exceptionGroupHashExpr("exception_hash", aliases)
+	args := append(append([]interface{}{projectId}, groupArgs...), exceptionHash)
+	args = append(args, filterArgs...)
 
 	// Get grouped info
 	var group models.ExceptionGroup
 	err := (*chdb.Conn).QueryRow(ctx,
-		"SELECT exception_hash, any(stack_trace), max(recorded_at) as last_seen, min(recorded_at) as first_seen, count() as count FROM exception_stack_traces WHERE project_id = ? AND exception_hash = ? GROUP BY exception_hash",
-		projectId, exceptionHash).Scan(&group.ExceptionHash, &group.StackTrace, &group.LastSeen, &group.FirstSeen, &group.Count)
+		"SELECT ? as group_hash, any(stack_trace), max(recorded_at) as last_seen, min(recorded_at) as first_seen, count() as count, argMinIf(app_version, recorded_at, app_version != '') as first_seen_release, argMax(exception_type, recorded_at) as exception_type FROM exception_stack_traces WHERE project_id = ? AND "+groupHash+" = ?"+filterSQL+" GROUP BY group_hash",
+		append([]interface{}{exceptionHash}, args...)...).Scan(&group.ExceptionHash, &group.StackTrace, &group.LastSeen, &group.FirstSeen, &group.Count, &group.FirstSeenRelease, &group.ExceptionType)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil, 0, nil
@@ -160,8 +195,8 @@ func (e *exceptionStackTraceRepository) FindByHash(ctx context.Context, projectI
 	}
 
 	rows, err := (*chdb.Conn).Query(ctx,
-		"SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, recorded_at, attributes, app_version, server_name, is_message FROM exception_stack_traces WHERE project_id = ? AND exception_hash = ? ORDER BY recorded_at DESC LIMIT ? OFFSET ?",
-		projectId, exceptionHash, pageSize, offset)
+		"SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, raw_stack_trace, debug_ids, recorded_at, attributes, app_version, server_name, environment, is_message, exception_type, exception_message, frames FROM exception_stack_traces WHERE project_id = ? AND "+groupHash+" = ?"+filterSQL+" ORDER BY recorded_at DESC LIMIT ? OFFSET ?",
+		append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -172,10 +207,12 @@ func (e *exceptionStackTraceRepository) FindByHash(ctx context.Context, projectI
 		var o models.ExceptionStackTrace
 		var attributesJSON string
 		var isMessage uint8
-		if err := rows.Scan(&o.Id, &o.ProjectId, &o.TraceId, &o.TraceType, &o.ExceptionHash, &o.StackTrace, &o.RecordedAt, &attributesJSON, &o.AppVersion, &o.ServerName, &isMessage); err != nil {
+		var framesJSON string
+		if err := rows.Scan(&o.Id, &o.ProjectId, &o.TraceId, &o.TraceType, &o.ExceptionHash, &o.StackTrace, &o.RawStackTrace, &o.DebugIds, &o.RecordedAt, &attributesJSON, &o.AppVersion, &o.ServerName, &o.Environment, &isMessage, &o.ExceptionType, &o.ExceptionMessage, &framesJSON); err != nil {
 			return nil, nil, 0, err
 		}
 		o.IsMessage = isMessage == 1
+		o.Frames = decodeFrames(framesJSON)
 		if attributesJSON != "" && attributesJSON != "{}" {
 			if err := json.Unmarshal([]byte(attributesJSON), &o.Attributes); err != nil {
 				o.Attributes = nil // If parsing fails, leave attributes as nil
@@ -187,6 +224,65 @@ func (e *exceptionStackTraceRepository) FindByHash(ctx context.Context, projectI
 	return &group, occurrences, int64(group.Count), nil
 }
 
+// ExceptionFacetLimit is the number of top values returned per facet key
+const ExceptionFacetLimit = 10
+
+// FindFacets returns the value distribution of the server name, app version, trace type, environment
+// and every attribute key across the occurrences of an exception group
+func (e *exceptionStackTraceRepository) FindFacets(ctx context.Context, projectId uuid.UUID, exceptionHash string, aliases map[string]string, filter models.TelemetryFilter) ([]models.ExceptionFacet, error) {
+	filterSQL, filterArgs := filterClause(filter, "")
+	groupHash, groupArgs := exceptionGroupHashExpr("exception_hash", aliases)
+
+	// the per key total is computed before LIMIT BY drops the less common values
+	query := `SELECT
+		facet.1 as key,
+		facet.2 as value,
+		count() as value_count,
+		sum(count()) OVER (PARTITION BY key) as key_total
+	FROM exception_stack_traces
+	ARRAY JOIN arrayConcat(
+		[(?, toString(server_name)), (?, toString(app_version)), (?, toString(trace_type)), (?, toString(environment))],
+		arrayZip(mapKeys(attributes_map), mapValues(attributes_map))
+	) as facet
+	WHERE project_id = ? AND ` + groupHash + ` = ?` + filterSQL + ` AND facet.2 != ''
+	GROUP BY key, value
+	ORDER BY key ASC, value_count DESC
+	LIMIT ? BY key`
+
+	args := []interface{}{
+		models.ExceptionFacetServerName, models.ExceptionFacetAppVersion, models.ExceptionFacetTraceType, models.ExceptionFacetEnvironment,
+		projectId,
+	}
+	args = append(append(args, groupArgs...), exceptionHash)
+	args = append(args, filterArgs...)
+	args = append(args, ExceptionFacetLimit)
+
+	rows, err := (*chdb.Conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := []models.ExceptionFacet{}
+	for rows.Next() {
+		var key string
+		var value models.ExceptionFacetValue
+		var total uint64
+		if err := rows.Scan(&key, &value.Value, &value.Count, &total); err != nil {
+			return nil, err
+		}
+		if total > 0 {
+			value.Percentage = float64(value.Count) / float64(total) * 100
+		}
+		if len(facets) == 0 || facets[len(facets)-1].Key != key {
+			facets = append(facets, models.ExceptionFacet{Key: key, Total: total})
+		}
+		facets[len(facets)-1].Values = append(facets[len(facets)-1].Values, value)
+	}
+
+	return facets, nil
+}
+
 // CountByHour returns exception counts grouped by hour
 func (e *exceptionStackTraceRepository) CountByHour(ctx context.Context, projectId uuid.UUID, start, end time.Time) ([]models.TimeSeriesPoint, error) {
 	query := `SELECT
@@ -216,16 +312,18 @@ func (e *exceptionStackTraceRepository) CountByHour(ctx context.Context, project
 }
 
 // CountByInterval returns exception counts grouped by configurable interval in minutes
-func (e *exceptionStackTraceRepository) CountByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int) ([]models.TimeSeriesPoint, error) {
+func (e *exceptionStackTraceRepository) CountByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.TimeSeriesPoint, error) {
+	filterSQL, filterArgs := filterClause(filter, "")
+
 	query := `SELECT
 		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
 		toFloat64(count()) as count
 	FROM exception_stack_traces
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY bucket
 	ORDER BY bucket ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, intervalMinutes, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -243,22 +341,26 @@ func (e *exceptionStackTraceRepository) CountByInterval(ctx context.Context, pro
 	return points, nil
 }
 
-// GetHourlyTrendForHashes returns hourly counts for specific exception hashes
-func (e *exceptionStackTraceRepository) GetHourlyTrendForHashes(ctx context.Context, projectId uuid.UUID, hashes []string, start, end time.Time) (map[string][]models.ExceptionTrendPoint, error) {
+// GetHourlyTrendForHashes returns hourly counts for specific exception groups, keyed by their canonical hash
+func (e *exceptionStackTraceRepository) GetHourlyTrendForHashes(ctx context.Context, projectId uuid.UUID, hashes []string, aliases map[string]string, start, end time.Time, filter models.TelemetryFilter) (map[string][]models.ExceptionTrendPoint, error) {
 	if len(hashes) == 0 {
 		return make(map[string][]models.ExceptionTrendPoint), nil
 	}
 
+	filterSQL, filterArgs := filterClause(filter, "")
+	groupHash, groupArgs := exceptionGroupHashExpr("exception_hash", aliases)
+
 	query := `SELECT
-		exception_hash,
+		` + groupHash + ` as group_hash,
 		toStartOfHour(recorded_at) as hour,
 		count() as count
 	FROM exception_stack_traces
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND exception_hash IN (?)
-	GROUP BY exception_hash, hour
-	ORDER BY exception_hash, hour ASC`
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND group_hash IN (?)` + filterSQL + `
+	GROUP BY group_hash, hour
+	ORDER BY group_hash, hour ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, start, end, hashes)
+	args := append(append([]interface{}{}, groupArgs...), projectId, start, end, hashes)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs(args, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -277,6 +379,73 @@ func (e *exceptionStackTraceRepository) GetHourlyTrendForHashes(ctx context.Cont
 	return result, nil
 }
 
+// CountByHashForVersions returns per exception group occurrence counts in the base and target app versions,
+// merged hashes are counted under the canonical hash of their group
+func (e *exceptionStackTraceRepository) CountByHashForVersions(ctx context.Context, projectId uuid.UUID, baseVersion, targetVersion string, start, end time.Time, aliases map[string]string, filter models.TelemetryFilter) ([]models.VersionExceptionCounts, error) {
+	filterSQL, filterArgs := filterClause(filter, "")
+	groupHash, groupArgs := exceptionGroupHashExpr("exception_hash", aliases)
+
+	query := `SELECT
+		` + groupHash + ` as group_hash,
+		argMax(stack_trace, recorded_at) as stack_trace,
+		any(is_message) as is_message,
+		countIf(app_version = ?) as base_count,
+		countIf(app_version = ?) as target_count
+	FROM exception_stack_traces
+	WHERE project_id = ? AND app_version IN (?) AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
+	GROUP BY group_hash
+	ORDER BY target_count DESC`
+
+	args := append(groupArgs, baseVersion, targetVersion, projectId, []string{baseVersion, targetVersion}, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs(args, filterArgs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []models.VersionExceptionCounts
+	for rows.Next() {
+		var c models.VersionExceptionCounts
+		if err := rows.Scan(&c.ExceptionHash, &c.StackTrace, &c.IsMessage, &c.BaseCount, &c.TargetCount); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, nil
+}
+
+// decodeFrames reads the stored frames of an exception, exceptions stored before frames were parsed have none
+func decodeFrames(framesJSON string) []models.ExceptionFrame {
+	var frames []models.ExceptionFrame
+	if framesJSON != "" && framesJSON != "[]" {
+		if err := json.Unmarshal([]byte(framesJSON), &frames); err != nil {
+			return nil
+		}
+	}
+	return frames
+}
+
+// exceptionGroupHashExpr maps an exception hash column to the canonical hash of its group,
+// the merged hashes and their canonical hashes are bound as the two arrays of transform
+func exceptionGroupHashExpr(column string, aliases map[string]string) (string, []interface{}) {
+	if len(aliases) == 0 {
+		return column, nil
+	}
+
+	merged := make([]string, 0, len(aliases))
+	for hash := range aliases {
+		merged = append(merged, hash)
+	}
+	sort.Strings(merged)
+	canonical := make([]string, len(merged))
+	for i, hash := range merged {
+		canonical[i] = aliases[hash]
+	}
+
+	return "transform(" + column + ", ?, ?, " + column + ")", []interface{}{merged, canonical}
+}
+
 // ArchiveByHashes archives exceptions by their hashes
 func (e *exceptionStackTraceRepository) ArchiveByHashes(ctx context.Context, projectId uuid.UUID, hashes []string) error {
 	if len(hashes) == 0 {
@@ -324,15 +493,16 @@ func (e *exceptionStackTraceRepository) FindExceptionByTraceId(ctx context.Conte
 	var est models.ExceptionStackTrace
 	var attributesJSON string
 	var isMessage uint8
+	var framesJSON string
 
 	err := (*chdb.Conn).QueryRow(ctx,
-		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, recorded_at, attributes, app_version, server_name, is_message
+		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, raw_stack_trace, debug_ids, recorded_at, attributes, app_version, server_name, environment, is_message, exception_type, exception_message, frames
 		FROM exception_stack_traces
 		WHERE project_id = ? AND trace_id = ? AND is_message = false
 		LIMIT 1`,
 		projectId, traceId).Scan(
-		&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace,
-		&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &isMessage)
+		&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace, &est.RawStackTrace, &est.DebugIds,
+		&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &est.Environment, &isMessage, &est.ExceptionType, &est.ExceptionMessage, &framesJSON)
 
 	if err != nil {
 		// No exception found for this trace
@@ -340,6 +510,7 @@ func (e *exceptionStackTraceRepository) FindExceptionByTraceId(ctx context.Conte
 	}
 
 	est.IsMessage = isMessage == 1
+	est.Frames = decodeFrames(framesJSON)
 	if attributesJSON != "" && attributesJSON != "{}" {
 		if err := json.Unmarshal([]byte(attributesJSON), &est.Attributes); err != nil {
 			est.Attributes = nil
@@ -352,7 +523,7 @@ func (e *exceptionStackTraceRepository) FindExceptionByTraceId(ctx context.Conte
 // FindAllByTraceId returns all exceptions and messages associated with a specific trace
 func (e *exceptionStackTraceRepository) FindAllByTraceId(ctx context.Context, projectId uuid.UUID, traceId uuid.UUID) ([]models.ExceptionStackTrace, error) {
 	rows, err := (*chdb.Conn).Query(ctx,
-		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, recorded_at, attributes, app_version, server_name, is_message
+		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, raw_stack_trace, debug_ids, recorded_at, attributes, app_version, server_name, environment, is_message, exception_type, exception_message, frames
 		FROM exception_stack_traces
 		WHERE project_id = ? AND trace_id = ?
 		ORDER BY recorded_at ASC`,
@@ -368,13 +539,58 @@ func (e *exceptionStackTraceRepository) FindAllByTraceId(ctx context.Context, pr
 		var est models.ExceptionStackTrace
 		var attributesJSON string
 		var isMessage uint8
+		var framesJSON string
+
+		if err := rows.Scan(&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace, &est.RawStackTrace, &est.DebugIds,
+			&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &est.Environment, &isMessage, &est.ExceptionType, &est.ExceptionMessage, &framesJSON); err != nil {
+			return nil, err
+		}
+
+		est.IsMessage = isMessage == 1
+		est.Frames = decodeFrames(framesJSON)
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &est.Attributes); err != nil {
+				est.Attributes = nil
+			}
+		}
+		results = append(results, est)
+	}
+
+	return results, nil
+}
+
+// FindAllByTraceIdInProjects returns the exceptions and messages of a trace across several projects
+func (e *exceptionStackTraceRepository) FindAllByTraceIdInProjects(ctx context.Context, projectIds []uuid.UUID, traceId uuid.UUID) ([]models.ExceptionStackTrace, error) {
+	if len(projectIds) == 0 {
+		return nil, nil
+	}
+
+	rows, err := (*chdb.Conn).Query(ctx,
+		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, raw_stack_trace, debug_ids, recorded_at, attributes, app_version, server_name, environment, is_message, exception_type, exception_message, frames
+		FROM exception_stack_traces
+		WHERE project_id IN (?) AND trace_id = ?
+		ORDER BY recorded_at ASC`,
+		projectIds, traceId)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.ExceptionStackTrace
+	for rows.Next() {
+		var est models.ExceptionStackTrace
+		var attributesJSON string
+		var isMessage uint8
+		var framesJSON string
 
-		if err := rows.Scan(&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace,
-			&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &isMessage); err != nil {
+		if err := rows.Scan(&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace, &est.RawStackTrace, &est.DebugIds,
+			&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &est.Environment, &isMessage, &est.ExceptionType, &est.ExceptionMessage, &framesJSON); err != nil {
 			return nil, err
 		}
 
 		est.IsMessage = isMessage == 1
+		est.Frames = decodeFrames(framesJSON)
 		if attributesJSON != "" && attributesJSON != "{}" {
 			if err := json.Unmarshal([]byte(attributesJSON), &est.Attributes); err != nil {
 				est.Attributes = nil
@@ -386,20 +602,86 @@ func (e *exceptionStackTraceRepository) FindAllByTraceId(ctx context.Context, pr
 	return results, nil
 }
 
+// FindRecentByVersion returns the most recent exceptions of an app version, messages aren't resolved so they are skipped
+func (e *exceptionStackTraceRepository) FindRecentByVersion(ctx context.Context, projectId uuid.UUID, version string, since time.Time, limit int) ([]models.ExceptionStackTrace, error) {
+	return e.findRecent(ctx, projectId, "app_version = ?", version, since, limit)
+}
+
+// FindRecentByDebugIds returns the most recent exceptions thrown in scripts built with any of the debug IDs
+func (e *exceptionStackTraceRepository) FindRecentByDebugIds(ctx context.Context, projectId uuid.UUID, debugIds []string, since time.Time, limit int) ([]models.ExceptionStackTrace, error) {
+	return e.findRecent(ctx, projectId, "hasAny(mapValues(debug_ids), ?)", debugIds, since, limit)
+}
+
+func (e *exceptionStackTraceRepository) findRecent(ctx context.Context, projectId uuid.UUID, condition string, value interface{}, since time.Time, limit int) ([]models.ExceptionStackTrace, error) {
+	rows, err := (*chdb.Conn).Query(ctx,
+		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, raw_stack_trace, debug_ids, recorded_at, attributes, app_version, server_name, environment, is_message, exception_type, exception_message, frames
+		FROM exception_stack_traces
+		WHERE project_id = ? AND `+condition+` AND recorded_at >= ? AND is_message = 0
+		ORDER BY recorded_at DESC
+		LIMIT ?`,
+		projectId, value, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.ExceptionStackTrace
+	for rows.Next() {
+		var est models.ExceptionStackTrace
+		var attributesJSON string
+		var isMessage uint8
+		var framesJSON string
+		if err := rows.Scan(&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace, &est.RawStackTrace, &est.DebugIds,
+			&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &est.Environment, &isMessage, &est.ExceptionType, &est.ExceptionMessage, &framesJSON); err != nil {
+			return nil, err
+		}
+		est.IsMessage = isMessage == 1
+		est.Frames = decodeFrames(framesJSON)
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &est.Attributes); err != nil {
+				est.Attributes = nil
+			}
+		}
+		results = append(results, est)
+	}
+
+	return results, nil
+}
+
+// Replace rewrites stored exceptions, the hash is part of the sorting key so rows are inserted again instead of
+// updated. The rewritten rows are inserted first and only then the previous versions deleted, matched on what they
+// stored so the new rows are kept, a failed insert leaves the exceptions as they were.
+func (e *exceptionStackTraceRepository) Replace(ctx context.Context, projectId uuid.UUID, previous, exceptions []models.ExceptionStackTrace) error {
+	if len(exceptions) == 0 {
+		return nil
+	}
+	if err := e.InsertAsync(ctx, exceptions); err != nil {
+		return err
+	}
+
+	replaced := make([]clickhouse.GroupSet, len(previous))
+	for i, est := range previous {
+		replaced[i] = clickhouse.GroupSet{Value: []any{est.Id, est.ExceptionHash, est.StackTrace}}
+	}
+	// the lightweight delete hides the rows before it returns, a mutation would leave them counted for a while
+	return (*chdb.Conn).Exec(ctx, "DELETE FROM exception_stack_traces WHERE project_id = ? AND (id, exception_hash, stack_trace) IN (?)", projectId, replaced)
+}
+
 // FindById returns a single exception by its ID
 func (e *exceptionStackTraceRepository) FindById(ctx context.Context, projectId uuid.UUID, id uuid.UUID) (*models.ExceptionStackTrace, error) {
 	var est models.ExceptionStackTrace
 	var attributesJSON string
 	var isMessage uint8
+	var framesJSON string
 
 	err := (*chdb.Conn).QueryRow(ctx,
-		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, recorded_at, attributes, app_version, server_name, is_message
+		`SELECT id, project_id, trace_id, trace_type, exception_hash, stack_trace, raw_stack_trace, debug_ids, recorded_at, attributes, app_version, server_name, environment, is_message, exception_type, exception_message, frames
 		FROM exception_stack_traces
 		WHERE project_id = ? AND id = ?
 		LIMIT 1`,
 		projectId, id).Scan(
-		&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace,
-		&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &isMessage)
+		&est.Id, &est.ProjectId, &est.TraceId, &est.TraceType, &est.ExceptionHash, &est.StackTrace, &est.RawStackTrace, &est.DebugIds,
+		&est.RecordedAt, &attributesJSON, &est.AppVersion, &est.ServerName, &est.Environment, &isMessage, &est.ExceptionType, &est.ExceptionMessage, &framesJSON)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -409,6 +691,7 @@ func (e *exceptionStackTraceRepository) FindById(ctx context.Context, projectId
 	}
 
 	est.IsMessage = isMessage == 1
+	est.Frames = decodeFrames(framesJSON)
 	if attributesJSON != "" && attributesJSON != "{}" {
 		if err := json.Unmarshal([]byte(attributesJSON), &est.Attributes); err != nil {
 			est.Attributes = nil
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"backend/app/models"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tracewayapp/go-lightning/lit"
+)
+
+type healthThresholdRepository struct{}
+
+func (r *healthThresholdRepository) FindByProject(tx *sql.Tx, projectId uuid.UUID) ([]*models.HealthThreshold, error) {
+	return lit.Select[models.HealthThreshold](
+		tx,
+		"SELECT id, project_id, metric_type, warning, critical, updated_at FROM health_thresholds WHERE project_id = $1",
+		projectId,
+	)
+}
+
+// Upsert sets the thresholds of a metric type, relying on the unique (project_id, metric_type) constraint so concurrent
+// updates of the same metric don't insert it twice
+func (r *healthThresholdRepository) Upsert(tx *sql.Tx, projectId uuid.UUID, metricType string, warning, critical float64) error {
+	_, err := tx.Exec(
+		`INSERT INTO health_thresholds (project_id, metric_type, warning, critical, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (project_id, metric_type) DO UPDATE SET warning = EXCLUDED.warning, critical = EXCLUDED.critical, updated_at = EXCLUDED.updated_at`,
+		projectId,
+		metricType,
+		warning,
+		critical,
+		time.Now().UTC(),
+	)
+	return err
+}
+
+func (r *healthThresholdRepository) DeleteByProjectAndMetricType(tx *sql.Tx, projectId uuid.UUID, metricType string) error {
+	return lit.Delete(tx, "DELETE FROM health_thresholds WHERE project_id = $1 AND metric_type = $2", projectId, metricType)
+}
+
+// LoadProfile returns the effective thresholds for a project, falling back to the defaults for metric types without an override
+func (r *healthThresholdRepository) LoadProfile(tx *sql.Tx, projectId uuid.UUID) (models.HealthThresholdProfile, error) {
+	overrides, err := r.FindByProject(tx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewHealthThresholdProfile(overrides), nil
+}
+
+var HealthThresholdRepository = healthThresholdRepository{}
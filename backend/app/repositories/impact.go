@@ -21,7 +21,7 @@ func computeImpactReason(
 	offsetNs := float64(offsetMs) * 1_000_000
 
 	// 1. Inverted Apdex
-	apdexScore := 1.0 - (float64(satisfiedCount)+float64(toleratingCount)*0.5)/totalF
+	invertedApdex := 1.0 - apdexScore(total, satisfiedCount, toleratingCount)
 
 	// 2. Error Rate Floor
 	badRate := badF / totalF
@@ -80,7 +80,7 @@ func computeImpactReason(
 		volumeScore = 0.25
 	}
 
-	maxScore := math.Max(apdexScore, math.Max(errorRateScore, math.Max(p99Score, math.Max(clientErrorScore, volumeScore))))
+	maxScore := math.Max(invertedApdex, math.Max(errorRateScore, math.Max(p99Score, math.Max(clientErrorScore, volumeScore))))
 
 	if maxScore < 0.25 {
 		return "Endpoint is healthy"
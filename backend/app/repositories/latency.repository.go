@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"backend/app/chdb"
+	"backend/app/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type latencyRepository struct{}
+
+type latencySource struct {
+	table      string
+	nameColumn string
+}
+
+var latencySources = map[string]latencySource{
+	models.LatencySourceEndpoint: {table: "endpoints", nameColumn: "endpoint"},
+	models.LatencySourceTask:     {table: "tasks", nameColumn: "task_name"},
+}
+
+// latencyBucketExpr computes the same bucket as services.LatencyBucketIndex
+var latencyBucketExpr = fmt.Sprintf("least(toInt32(floor(log2(greatest(duration, %d) / %d) * %d)), %d)",
+	int64(models.LatencyBucketBase), int64(models.LatencyBucketBase), models.LatencyBucketsPerDoubling, models.LatencyMaxBucket)
+
+func (r *latencyRepository) source(sourceType string) (latencySource, error) {
+	source, ok := latencySources[sourceType]
+	if !ok {
+		return latencySource{}, fmt.Errorf("unknown latency source %q", sourceType)
+	}
+	return source, nil
+}
+
+// FindBucketCounts returns the number of executions of an endpoint or task per latency bucket
+func (r *latencyRepository) FindBucketCounts(ctx context.Context, sourceType string, projectId uuid.UUID, name string, start, end time.Time, filter models.TelemetryFilter) ([]models.LatencyBucketCount, error) {
+	source, err := r.source(sourceType)
+	if err != nil {
+		return nil, err
+	}
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
+	query := `SELECT ` + latencyBucketExpr + ` as bucket, count()
+	FROM ` + source.table + `
+	WHERE project_id = ? AND ` + source.nameColumn + ` = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
+	GROUP BY bucket
+	ORDER BY bucket ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{projectId, name, start, end}, filterArgs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []models.LatencyBucketCount
+	for rows.Next() {
+		var c models.LatencyBucketCount
+		var bucket int32
+		if err := rows.Scan(&bucket, &c.Count); err != nil {
+			return nil, err
+		}
+		c.Bucket = int(bucket)
+		counts = append(counts, c)
+	}
+
+	return counts, nil
+}
+
+// FindHeatmapCells returns the number of executions of an endpoint or task per time interval and latency bucket
+func (r *latencyRepository) FindHeatmapCells(ctx context.Context, sourceType string, projectId uuid.UUID, name string, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.LatencyHeatmapCell, error) {
+	source, err := r.source(sourceType)
+	if err != nil {
+		return nil, err
+	}
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
+	query := `SELECT
+		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as interval_start,
+		` + latencyBucketExpr + ` as bucket,
+		count()
+	FROM ` + source.table + `
+	WHERE project_id = ? AND ` + source.nameColumn + ` = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
+	GROUP BY interval_start, bucket
+	ORDER BY interval_start ASC, bucket ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, name, start, end}, filterArgs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cells []models.LatencyHeatmapCell
+	for rows.Next() {
+		var c models.LatencyHeatmapCell
+		var bucket int32
+		if err := rows.Scan(&c.Timestamp, &bucket, &c.Count); err != nil {
+			return nil, err
+		}
+		c.Bucket = int(bucket)
+		cells = append(cells, c)
+	}
+
+	return cells, nil
+}
+
+var LatencyRepository = latencyRepository{}
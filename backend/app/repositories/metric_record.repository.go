@@ -13,21 +13,22 @@ import (
 type metricRecordRepository struct{}
 
 func (e *metricRecordRepository) InsertAsync(ctx context.Context, lines []models.MetricRecord) error {
-	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO metric_records (project_id, name, value, recorded_at, server_name)")
+	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO metric_records (project_id, name, value, recorded_at, server_name, environment)")
 	if err != nil {
 		return err
 	}
 	for _, m := range lines {
-		if err := batch.Append(m.ProjectId, m.Name, m.Value, m.RecordedAt, m.ServerName); err != nil {
+		if err := batch.Append(m.ProjectId, m.Name, m.Value, m.RecordedAt, m.ServerName, m.Environment); err != nil {
 			return err
 		}
 	}
 	return batch.Send()
 }
 
-func (e *metricRecordRepository) GetAverageBetween(ctx context.Context, projectId uuid.UUID, name string, start, end time.Time) (float64, error) {
+func (e *metricRecordRepository) GetAverageBetween(ctx context.Context, projectId uuid.UUID, name string, start, end time.Time, filter models.TelemetryFilter) (float64, error) {
+	filterSQL, filterArgs := filterClause(filter, "")
 	var avg float64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT coalesce(avg(value), 0) FROM metric_records WHERE project_id = ? AND name = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, name, start, end).Scan(&avg)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT coalesce(avg(value), 0) FROM metric_records WHERE project_id = ? AND name = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, withFilterArgs([]interface{}{projectId, name, start, end}, filterArgs)...).Scan(&avg)
 	return avg, err
 }
 
@@ -88,13 +89,14 @@ func (e *metricRecordRepository) GetAverageByInterval(ctx context.Context, proje
 }
 
 // GetDistinctServers returns all unique server names with data in the time range
-func (e *metricRecordRepository) GetDistinctServers(ctx context.Context, projectId uuid.UUID, start, end time.Time) ([]string, error) {
+func (e *metricRecordRepository) GetDistinctServers(ctx context.Context, projectId uuid.UUID, start, end time.Time, filter models.TelemetryFilter) ([]string, error) {
+	filterSQL, filterArgs := filterClause(filter, "")
 	query := `SELECT DISTINCT server_name FROM metric_records
               WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
-              AND server_name != ''
+              AND server_name != ''` + filterSQL + `
               ORDER BY server_name ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +114,7 @@ func (e *metricRecordRepository) GetDistinctServers(ctx context.Context, project
 }
 
 // GetAverageByIntervalPerServer returns metric averages grouped by interval and server
-func (e *metricRecordRepository) GetAverageByIntervalPerServer(ctx context.Context, projectId uuid.UUID, name string, start, end time.Time, intervalMinutes int, servers []string) (map[string][]models.TimeSeriesPoint, error) {
+func (e *metricRecordRepository) GetAverageByIntervalPerServer(ctx context.Context, projectId uuid.UUID, name string, start, end time.Time, intervalMinutes int, servers []string, filter models.TelemetryFilter) (map[string][]models.TimeSeriesPoint, error) {
 	query := `SELECT
 		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
 		server_name,
@@ -129,6 +131,10 @@ func (e *metricRecordRepository) GetAverageByIntervalPerServer(ctx context.Conte
 		query += " AND server_name != ''"
 	}
 
+	filterSQL, filterArgs := filterClause(filter, "")
+	query += filterSQL
+	args = append(args, filterArgs...)
+
 	query += " GROUP BY bucket, server_name ORDER BY bucket ASC, server_name ASC"
 
 	rows, err := (*chdb.Conn).Query(ctx, query, args...)
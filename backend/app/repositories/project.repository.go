@@ -132,6 +132,19 @@ func (p *projectRepository) FindByOrganizationId(tx *sql.Tx, organizationId int)
 	)
 }
 
+// FindInSameOrganization returns the projects of the organization the project belongs to,
+// only the project itself when it has no organization
+func (p *projectRepository) FindInSameOrganization(tx *sql.Tx, projectId uuid.UUID) ([]*models.Project, error) {
+	project, err := p.FindById(tx, projectId)
+	if err != nil || project == nil {
+		return nil, err
+	}
+	if project.OrganizationId == nil {
+		return []*models.Project{project}, nil
+	}
+	return p.FindByOrganizationId(tx, *project.OrganizationId)
+}
+
 // FindByUserId returns all projects belonging to organizations the user is a member of
 func (p *projectRepository) FindByUserId(tx *sql.Tx, userId int) ([]*models.Project, error) {
 	return lit.Select[models.Project](
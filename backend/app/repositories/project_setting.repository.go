@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"backend/app/models"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tracewayapp/go-lightning/lit"
+)
+
+type projectSettingRepository struct{}
+
+func (r *projectSettingRepository) FindByProject(tx *sql.Tx, projectId uuid.UUID) (*models.ProjectSetting, error) {
+	return lit.SelectSingle[models.ProjectSetting](
+		tx,
+		"SELECT id, project_id, apdex_threshold_ms, user_identity_attribute, in_app_prefixes, updated_at FROM project_settings WHERE project_id = $1",
+		projectId,
+	)
+}
+
+// FindByProjectOrDefault returns the stored settings of a project or the defaults when none were saved yet
+func (r *projectSettingRepository) FindByProjectOrDefault(tx *sql.Tx, projectId uuid.UUID) (*models.ProjectSetting, error) {
+	setting, err := r.FindByProject(tx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return models.DefaultProjectSetting(projectId), nil
+	}
+	return setting, nil
+}
+
+// Save stores the settings of a project, creating the row the first time they're saved
+func (r *projectSettingRepository) Save(tx *sql.Tx, setting *models.ProjectSetting) (*models.ProjectSetting, error) {
+	return lit.SelectSingle[models.ProjectSetting](
+		tx,
+		`INSERT INTO project_settings (project_id, apdex_threshold_ms, user_identity_attribute, in_app_prefixes, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (project_id) DO UPDATE SET apdex_threshold_ms = EXCLUDED.apdex_threshold_ms, user_identity_attribute = EXCLUDED.user_identity_attribute, in_app_prefixes = EXCLUDED.in_app_prefixes, updated_at = EXCLUDED.updated_at
+		RETURNING id, project_id, apdex_threshold_ms, user_identity_attribute, in_app_prefixes, updated_at`,
+		setting.ProjectId,
+		setting.ApdexThresholdMs,
+		setting.UserIdentityAttribute,
+		setting.InAppPrefixes,
+		time.Now().UTC(),
+	)
+}
+
+var ProjectSettingRepository = projectSettingRepository{}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"backend/app/chdb"
+	"backend/app/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type queryRepository struct{}
+
+// traceCallersJoin maps the trace ids of the spans aliased by alias to the endpoint or task that started them,
+// its args are projectId, fromDate, toDate twice
+func traceCallersJoin(alias string) string {
+	return `LEFT JOIN (
+		SELECT id, endpoint as name FROM endpoints
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+		UNION ALL
+		SELECT id, task_name as name FROM tasks
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	) callers ON ` + alias + `.trace_id = callers.id`
+}
+
+// FindQueries groups the database spans of the project by normalized statement
+func (r *queryRepository) FindQueries(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string, search string, filter models.TelemetryFilter) ([]models.QueryStats, int64, error) {
+	whereClause := "q.project_id = ? AND q.recorded_at >= ? AND q.recorded_at <= ? AND q.db_statement != ''"
+	args := []interface{}{projectId, fromDate, toDate}
+
+	if search != "" {
+		whereClause += " AND positionCaseInsensitive(q.db_statement, ?) > 0"
+		args = append(args, search)
+	}
+
+	filterSQL, filterArgs := filterClause(filter, "q")
+	whereClause += filterSQL
+	args = append(args, filterArgs...)
+
+	var count uint64
+	countQuery := "SELECT uniq(q.db_statement) FROM spans q WHERE " + whereClause
+	if err := (*chdb.Conn).QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+
+	orderByMap := map[string]string{
+		"count":          "total_count",
+		"total_duration": "total_duration",
+		"avg_duration":   "avg_duration",
+		"p95_duration":   "p95_duration",
+		"trace_count":    "trace_count",
+		"last_seen":      "last_seen",
+	}
+	orderExpr, ok := orderByMap[orderBy]
+	if !ok {
+		orderExpr = "total_duration"
+	}
+	sortDir := "DESC"
+	if sortDirection == "asc" {
+		sortDir = "ASC"
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `SELECT
+		q.db_statement,
+		any(q.db_system),
+		count() as total_count,
+		sum(q.duration) as total_duration,
+		avg(q.duration) as avg_duration,
+		quantile(0.95)(q.duration) as p95_duration,
+		uniq(q.trace_id) as trace_count,
+		max(q.recorded_at) as last_seen,
+		groupUniqArrayIf(5)(callers.name, callers.name != '') as caller_names
+	FROM spans q
+	` + traceCallersJoin("q") + `
+	WHERE ` + whereClause + `
+	GROUP BY q.db_statement
+	ORDER BY ` + orderExpr + ` ` + sortDir + `
+	LIMIT ? OFFSET ?`
+
+	queryArgs := []interface{}{projectId, fromDate, toDate, projectId, fromDate, toDate}
+	queryArgs = append(queryArgs, args...)
+	queryArgs = append(queryArgs, pageSize, offset)
+
+	rows, err := (*chdb.Conn).Query(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var stats []models.QueryStats
+	for rows.Next() {
+		var s models.QueryStats
+		var total int64
+		var avg, p95 float64
+		if err := rows.Scan(&s.Statement, &s.DbSystem, &s.Count, &total, &avg, &p95, &s.TraceCount, &s.LastSeen, &s.Callers); err != nil {
+			return nil, 0, err
+		}
+		s.TotalDuration = time.Duration(total)
+		s.AvgDuration = time.Duration(avg)
+		s.P95Duration = time.Duration(p95)
+		if s.TraceCount > 0 {
+			s.CallsPerTrace = float64(s.Count) / float64(s.TraceCount)
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, int64(count), nil
+}
+
+// FindNPlusOne returns the statements that ran at least minRepetitions times inside a single trace, most widespread first
+func (r *queryRepository) FindNPlusOne(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, minRepetitions int, filter models.TelemetryFilter) ([]models.NPlusOneQuery, error) {
+	filterSQL, filterArgs := filterClause(filter, "q")
+
+	query := `SELECT
+		db_statement,
+		any(db_system),
+		count() as affected_traces,
+		max(repetitions) as max_repetitions,
+		avg(repetitions),
+		sum(total_duration),
+		argMax(trace_id, repetitions),
+		groupUniqArrayIf(5)(caller, caller != '')
+	FROM (
+		SELECT
+			q.trace_id as trace_id,
+			q.db_statement as db_statement,
+			any(q.db_system) as db_system,
+			count() as repetitions,
+			sum(q.duration) as total_duration,
+			any(callers.name) as caller
+		FROM spans q
+		` + traceCallersJoin("q") + `
+		WHERE q.project_id = ? AND q.recorded_at >= ? AND q.recorded_at <= ? AND q.db_statement != ''` + filterSQL + `
+		GROUP BY q.trace_id, q.db_statement
+		HAVING repetitions >= ?
+	)
+	GROUP BY db_statement
+	ORDER BY affected_traces DESC, max_repetitions DESC
+	LIMIT 50`
+
+	args := []interface{}{projectId, fromDate, toDate, projectId, fromDate, toDate, projectId, fromDate, toDate}
+	args = append(args, filterArgs...)
+	args = append(args, minRepetitions)
+
+	rows, err := (*chdb.Conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queries []models.NPlusOneQuery
+	for rows.Next() {
+		var q models.NPlusOneQuery
+		var total int64
+		if err := rows.Scan(&q.Statement, &q.DbSystem, &q.AffectedTraces, &q.MaxRepetitions, &q.AvgRepetitions, &total, &q.ExampleTraceId, &q.Callers); err != nil {
+			return nil, err
+		}
+		q.TotalDuration = time.Duration(total)
+		queries = append(queries, q)
+	}
+
+	return queries, nil
+}
+
+var QueryRepository = queryRepository{}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"backend/app/models"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tracewayapp/go-lightning/lit"
+)
+
+type releaseRepository struct{}
+
+// Upsert registers a release, updating the commit and timestamp when the version was already registered for the environment
+func (r *releaseRepository) Upsert(tx *sql.Tx, release *models.Release) (*models.Release, error) {
+	return lit.SelectSingle[models.Release](
+		tx,
+		`INSERT INTO releases (project_id, version, environment, commit_sha, released_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (project_id, version, environment) DO UPDATE SET commit_sha = EXCLUDED.commit_sha, released_at = EXCLUDED.released_at
+		RETURNING id, project_id, version, environment, commit_sha, released_at, created_at`,
+		release.ProjectId,
+		release.Version,
+		release.Environment,
+		release.CommitSha,
+		release.ReleasedAt,
+		time.Now().UTC(),
+	)
+}
+
+func (r *releaseRepository) FindByProject(tx *sql.Tx, projectId uuid.UUID, limit int) ([]*models.ReleaseWithSourceMaps, error) {
+	return lit.Select[models.ReleaseWithSourceMaps](
+		tx,
+		`SELECT r.id, r.project_id, r.version, r.environment, r.commit_sha, r.released_at, r.created_at,
+			(SELECT COUNT(*) FROM source_maps sm WHERE sm.project_id = r.project_id AND sm.version = r.version) as source_map_count
+		FROM releases r
+		WHERE r.project_id = $1
+		ORDER BY r.released_at DESC
+		LIMIT $2`,
+		projectId,
+		limit,
+	)
+}
+
+func (r *releaseRepository) FindBetween(tx *sql.Tx, projectId uuid.UUID, start, end time.Time) ([]*models.Release, error) {
+	return lit.Select[models.Release](
+		tx,
+		`SELECT id, project_id, version, environment, commit_sha, released_at, created_at
+		FROM releases
+		WHERE project_id = $1 AND released_at >= $2 AND released_at <= $3
+		ORDER BY released_at ASC`,
+		projectId,
+		start,
+		end,
+	)
+}
+
+// FindLatestVersions returns the most recently released distinct versions, newest first
+func (r *releaseRepository) FindLatestVersions(tx *sql.Tx, projectId uuid.UUID, limit int) ([]string, error) {
+	rows, err := tx.Query(
+		`SELECT version
+		FROM releases
+		WHERE project_id = $1
+		GROUP BY version
+		ORDER BY MAX(released_at) DESC
+		LIMIT $2`,
+		projectId,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []string
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+var ReleaseRepository = releaseRepository{}
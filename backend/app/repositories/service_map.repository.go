@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"backend/app/chdb"
+	"backend/app/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type serviceMapRepository struct{}
+
+// ServiceMapBucket is the granularity of service_edges, it has to match toStartOfFiveMinutes in AggregateEdges
+const ServiceMapBucket = 5 * time.Minute
+
+// AggregateEdges computes the service edges of the spans recorded in [start, end) and replaces the buckets of the range
+// in service_edges with them, aggregating a range again doesn't count its spans twice.
+// Calls between instrumented services come from spans whose parent belongs to another service,
+// calls to external dependencies from client spans with a peer service and no instrumented child.
+// Trace ids aren't unique across organizations, so spans are only joined within the scope of their project
+// (see services.ServiceMapScopes) and spans of projects missing from scopes are ignored.
+func (r *serviceMapRepository) AggregateEdges(ctx context.Context, start, end time.Time, scopes map[string]string) error {
+	// parents can start well before their children, e.g. a long request that calls another service at its end
+	parentStart := start.Add(-time.Hour)
+
+	query := `INSERT INTO service_edges (project_id, target_project_id, bucket, source, target, target_type, calls, errors, duration_p95)
+	WITH ? AS scopes
+	SELECT project_id, target_project_id, bucket, source, target, target_type, count(), sum(is_error), quantileState(0.95)(duration)
+	FROM (
+		SELECT
+			p.project_id as project_id,
+			c.project_id as target_project_id,
+			toStartOfFiveMinutes(c.recorded_at) as bucket,
+			p.service_name as source,
+			c.service_name as target,
+			'service' as target_type,
+			c.is_error as is_error,
+			c.duration as duration
+		FROM (
+			SELECT trace_id, parent_id, project_id, service_name, recorded_at, is_error, duration, scopes[toString(project_id)] as scope
+			FROM spans
+			WHERE recorded_at >= ? AND recorded_at < ? AND service_name != ''
+		) c
+		INNER JOIN (
+			SELECT id, trace_id, project_id, service_name, scopes[toString(project_id)] as scope FROM spans
+			WHERE recorded_at >= ? AND recorded_at < ? AND service_name != ''
+		) p ON c.trace_id = p.trace_id AND c.parent_id = p.id AND c.scope = p.scope
+		WHERE c.scope != '' AND c.service_name != p.service_name
+
+		UNION ALL
+
+		SELECT
+			s.project_id as project_id,
+			s.project_id as target_project_id,
+			toStartOfFiveMinutes(s.recorded_at) as bucket,
+			s.service_name as source,
+			s.peer_service as target,
+			'external' as target_type,
+			s.is_error as is_error,
+			s.duration as duration
+		FROM (
+			SELECT id, trace_id, project_id, service_name, peer_service, recorded_at, is_error, duration, scopes[toString(project_id)] as scope
+			FROM spans
+			WHERE recorded_at >= ? AND recorded_at < ? AND service_name != '' AND peer_service != ''
+		) s
+		LEFT ANTI JOIN (
+			SELECT trace_id, assumeNotNull(parent_id) as parent_id, scopes[toString(project_id)] as scope FROM spans
+			WHERE recorded_at >= ? AND recorded_at < ? AND parent_id IS NOT NULL
+		) child ON s.trace_id = child.trace_id AND s.id = child.parent_id AND s.scope = child.scope
+		WHERE s.scope != ''
+	)
+	GROUP BY project_id, target_project_id, bucket, source, target, target_type`
+
+	// the lightweight delete hides the rows before it returns, a mutation could still be running during the insert
+	if err := (*chdb.Conn).Exec(ctx, "DELETE FROM service_edges WHERE bucket >= ? AND bucket < ?", start, end); err != nil {
+		return err
+	}
+
+	return (*chdb.Conn).Exec(ctx, query,
+		scopes,
+		start, end,
+		parentStart, end,
+		start, end,
+		start, end.Add(time.Hour))
+}
+
+// FindLatestBucket returns the most recent aggregated bucket, zero when nothing was aggregated yet
+func (r *serviceMapRepository) FindLatestBucket(ctx context.Context) (time.Time, error) {
+	var count uint64
+	var latest time.Time
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count(), max(bucket) FROM service_edges").Scan(&count, &latest)
+	if err != nil || count == 0 {
+		return time.Time{}, err
+	}
+	return latest, nil
+}
+
+// FindEdges returns the edges touching the project's services in the range, busiest first.
+// Both ends of an edge have to be among projectIds, the projects of the organization.
+func (r *serviceMapRepository) FindEdges(ctx context.Context, projectId uuid.UUID, projectIds []uuid.UUID, start, end time.Time) ([]models.ServiceEdge, error) {
+	query := `SELECT
+		source,
+		target,
+		target_type,
+		sum(calls) as total_calls,
+		sum(errors) as total_errors,
+		quantileMerge(0.95)(duration_p95) as p95_duration
+	FROM service_edges
+	WHERE (project_id = ? OR target_project_id = ?)
+		AND project_id IN (?) AND target_project_id IN (?)
+		AND bucket >= ? AND bucket <= ?
+	GROUP BY source, target, target_type
+	ORDER BY total_calls DESC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, projectId, projectId, projectIds, projectIds, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []models.ServiceEdge
+	for rows.Next() {
+		var e models.ServiceEdge
+		var p95 float64
+		if err := rows.Scan(&e.Source, &e.Target, &e.TargetType, &e.Calls, &e.Errors, &p95); err != nil {
+			return nil, err
+		}
+		e.P95Duration = time.Duration(p95)
+		if e.Calls > 0 {
+			e.ErrorRate = float64(e.Errors) / float64(e.Calls) * 100
+		}
+		edges = append(edges, e)
+	}
+
+	return edges, nil
+}
+
+var ServiceMapRepository = serviceMapRepository{}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"backend/app/models"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tracewayapp/go-lightning/lit"
+)
+
+type sloRepository struct{}
+
+func (r *sloRepository) Create(tx *sql.Tx, slo *models.Slo) (*models.Slo, error) {
+	now := time.Now().UTC()
+	slo.CreatedAt = now
+	slo.UpdatedAt = now
+	id, err := lit.Insert(tx, slo)
+	if err != nil {
+		return nil, err
+	}
+	slo.Id = id
+	return slo, nil
+}
+
+func (r *sloRepository) Update(tx *sql.Tx, slo *models.Slo) error {
+	slo.UpdatedAt = time.Now().UTC()
+	return lit.Update[models.Slo](tx, slo, "id = $1", slo.Id)
+}
+
+func (r *sloRepository) Delete(tx *sql.Tx, projectId uuid.UUID, id int) error {
+	return lit.Delete(tx, "DELETE FROM slos WHERE project_id = $1 AND id = $2", projectId, id)
+}
+
+func (r *sloRepository) FindByProject(tx *sql.Tx, projectId uuid.UUID) ([]*models.Slo, error) {
+	return lit.Select[models.Slo](
+		tx,
+		`SELECT id, project_id, name, endpoint, slo_type, target, latency_threshold_ms, window_days, created_at, updated_at
+		FROM slos
+		WHERE project_id = $1
+		ORDER BY created_at ASC`,
+		projectId,
+	)
+}
+
+func (r *sloRepository) FindById(tx *sql.Tx, projectId uuid.UUID, id int) (*models.Slo, error) {
+	return lit.SelectSingle[models.Slo](
+		tx,
+		`SELECT id, project_id, name, endpoint, slo_type, target, latency_threshold_ms, window_days, created_at, updated_at
+		FROM slos
+		WHERE project_id = $1 AND id = $2`,
+		projectId,
+		id,
+	)
+}
+
+var SloRepository = sloRepository{}
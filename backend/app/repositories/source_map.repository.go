@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/tracewayapp/go-lightning/lit"
 )
 
@@ -42,6 +43,14 @@ func (s *sourceMapRepository) FindLatestByProject(tx *sql.Tx, projectId uuid.UUI
 	)
 }
 
+// FindForVersion returns the source maps of a version, or those of the latest upload when the version is unknown
+func (s *sourceMapRepository) FindForVersion(tx *sql.Tx, projectId uuid.UUID, version string) ([]*models.SourceMap, error) {
+	if version != "" {
+		return s.FindByProjectAndVersion(tx, projectId, version)
+	}
+	return s.FindLatestByProject(tx, projectId)
+}
+
 func (s *sourceMapRepository) FindByProjectVersionAndFileName(tx *sql.Tx, projectId uuid.UUID, version, fileName string) (*models.SourceMap, error) {
 	return lit.SelectSingle[models.SourceMap](
 		tx,
@@ -52,4 +61,40 @@ func (s *sourceMapRepository) FindByProjectVersionAndFileName(tx *sql.Tx, projec
 	)
 }
 
+// FindByDebugIds returns the source maps built with the debug IDs in any version, oldest upload first
+func (s *sourceMapRepository) FindByDebugIds(tx *sql.Tx, projectId uuid.UUID, debugIds []string) ([]*models.SourceMap, error) {
+	return lit.Select[models.SourceMap](
+		tx,
+		"SELECT * FROM source_maps WHERE project_id = $1 AND debug_id = ANY($2) ORDER BY uploaded_at ASC",
+		projectId,
+		pq.Array(debugIds),
+	)
+}
+
+func (s *sourceMapRepository) FindByProjectAndId(tx *sql.Tx, projectId uuid.UUID, id int) (*models.SourceMap, error) {
+	return lit.SelectSingle[models.SourceMap](
+		tx,
+		"SELECT * FROM source_maps WHERE project_id = $1 AND id = $2",
+		projectId,
+		id,
+	)
+}
+
+// FindVersionsByProject returns the versions with uploaded source maps, most recently uploaded first
+func (s *sourceMapRepository) FindVersionsByProject(tx *sql.Tx, projectId uuid.UUID) ([]*models.SourceMapVersion, error) {
+	return lit.Select[models.SourceMapVersion](
+		tx,
+		`SELECT version, COUNT(*) as file_count, COALESCE(SUM(file_size), 0) as total_size, MAX(uploaded_at) as last_uploaded_at
+		FROM source_maps
+		WHERE project_id = $1
+		GROUP BY version
+		ORDER BY last_uploaded_at DESC`,
+		projectId,
+	)
+}
+
+func (s *sourceMapRepository) DeleteByProjectAndVersion(tx *sql.Tx, projectId uuid.UUID, version string) error {
+	return lit.Delete(tx, "DELETE FROM source_maps WHERE project_id = $1 AND version = $2", projectId, version)
+}
+
 var SourceMapRepository = sourceMapRepository{}
@@ -4,6 +4,7 @@ import (
 	"backend/app/chdb"
 	"backend/app/models"
 	"context"
+	"encoding/json"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/google/uuid"
@@ -17,12 +18,22 @@ func (r *spanRepository) InsertAsync(ctx context.Context, spans []models.Span) e
 	}
 
 	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)),
-		"INSERT INTO spans (id, trace_id, project_id, name, start_time, duration, recorded_at)")
+		"INSERT INTO spans (id, trace_id, project_id, name, start_time, duration, recorded_at, environment, parent_id, service_name, kind, attributes, is_error, peer_service, db_system, db_statement, http_method, http_host, http_route, http_status_code)")
 	if err != nil {
 		return err
 	}
 
 	for _, s := range spans {
+		attributesJSON := "{}"
+		if len(s.Attributes) != 0 {
+			if attributesBytes, err := json.Marshal(s.Attributes); err == nil {
+				attributesJSON = string(attributesBytes)
+			}
+		}
+		isError := uint8(0)
+		if s.IsError {
+			isError = 1
+		}
 		if err := batch.Append(
 			s.Id,
 			s.TraceId,
@@ -31,6 +42,19 @@ func (r *spanRepository) InsertAsync(ctx context.Context, spans []models.Span) e
 			s.StartTime,
 			s.Duration,
 			s.RecordedAt,
+			s.Environment,
+			s.ParentId,
+			s.ServiceName,
+			s.Kind,
+			attributesJSON,
+			isError,
+			s.PeerService,
+			s.DbSystem,
+			s.DbStatement,
+			s.HttpMethod,
+			s.HttpHost,
+			s.HttpRoute,
+			s.HttpStatusCode,
 		); err != nil {
 			return err
 		}
@@ -41,7 +65,8 @@ func (r *spanRepository) InsertAsync(ctx context.Context, spans []models.Span) e
 
 func (r *spanRepository) FindByTraceId(ctx context.Context, projectId, traceId uuid.UUID) ([]models.Span, error) {
 	query := `SELECT
-		id, trace_id, project_id, name, start_time, duration, recorded_at
+		id, trace_id, project_id, name, start_time, duration, recorded_at, environment, parent_id, service_name, kind, attributes, is_error, peer_service, db_system, db_statement,
+		http_method, http_host, http_route, http_status_code
 	FROM spans
 	WHERE project_id = ? AND trace_id = ?
 	ORDER BY start_time ASC`
@@ -55,12 +80,66 @@ func (r *spanRepository) FindByTraceId(ctx context.Context, projectId, traceId u
 	var spans []models.Span
 	for rows.Next() {
 		var s models.Span
+		var attributesJSON string
+		var isError uint8
 		if err := rows.Scan(
 			&s.Id, &s.TraceId, &s.ProjectId,
-			&s.Name, &s.StartTime, &s.Duration, &s.RecordedAt,
+			&s.Name, &s.StartTime, &s.Duration, &s.RecordedAt, &s.Environment,
+			&s.ParentId, &s.ServiceName, &s.Kind, &attributesJSON, &isError, &s.PeerService,
+			&s.DbSystem, &s.DbStatement, &s.HttpMethod, &s.HttpHost, &s.HttpRoute, &s.HttpStatusCode,
 		); err != nil {
 			return nil, err
 		}
+		s.IsError = isError == 1
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &s.Attributes); err != nil {
+				s.Attributes = nil
+			}
+		}
+		spans = append(spans, s)
+	}
+
+	return spans, nil
+}
+
+// FindByTraceIdInProjects returns the spans of a trace across several projects, used for distributed traces
+func (r *spanRepository) FindByTraceIdInProjects(ctx context.Context, projectIds []uuid.UUID, traceId uuid.UUID) ([]models.Span, error) {
+	if len(projectIds) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT
+		id, trace_id, project_id, name, start_time, duration, recorded_at, environment, parent_id, service_name, kind, attributes, is_error, peer_service, db_system, db_statement,
+		http_method, http_host, http_route, http_status_code
+	FROM spans
+	WHERE project_id IN (?) AND trace_id = ?
+	ORDER BY start_time ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, projectIds, traceId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spans []models.Span
+	for rows.Next() {
+		var s models.Span
+		var attributesJSON string
+		var isError uint8
+		if err := rows.Scan(
+			&s.Id, &s.TraceId, &s.ProjectId,
+			&s.Name, &s.StartTime, &s.Duration, &s.RecordedAt, &s.Environment,
+			&s.ParentId, &s.ServiceName, &s.Kind, &attributesJSON, &isError, &s.PeerService,
+			&s.DbSystem, &s.DbStatement, &s.HttpMethod, &s.HttpHost, &s.HttpRoute, &s.HttpStatusCode,
+		); err != nil {
+			return nil, err
+		}
+		s.IsError = isError == 1
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &s.Attributes); err != nil {
+				s.Attributes = nil
+			}
+		}
 		spans = append(spans, s)
 	}
 
@@ -16,7 +16,7 @@ import (
 type taskRepository struct{}
 
 func (e *taskRepository) InsertAsync(ctx context.Context, lines []models.Task) error {
-	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO tasks (id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name)")
+	batch, err := (*chdb.Conn).PrepareBatch(clickhouse.Context(context.Background(), clickhouse.WithAsync(false)), "INSERT INTO tasks (id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name, environment, resource_attributes)")
 	if err != nil {
 		return err
 	}
@@ -27,7 +27,7 @@ func (e *taskRepository) InsertAsync(ctx context.Context, lines []models.Task) e
 				attributesJSON = string(attributesBytes)
 			}
 		}
-		if err := batch.Append(t.Id, t.ProjectId, t.TaskName, t.Duration, t.RecordedAt, t.ClientIP, attributesJSON, t.AppVersion, t.ServerName); err != nil {
+		if err := batch.Append(t.Id, t.ProjectId, t.TaskName, t.Duration, t.RecordedAt, t.ClientIP, attributesJSON, t.AppVersion, t.ServerName, t.Environment, resourceAttributesOrEmpty(t.ResourceAttributes)); err != nil {
 			return err
 		}
 	}
@@ -40,9 +40,12 @@ func (e *taskRepository) CountBetween(ctx context.Context, projectId uuid.UUID,
 	return int64(count), err
 }
 
-func (e *taskRepository) FindAll(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string) ([]models.Task, int64, error) {
+func (e *taskRepository) FindAll(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, filter models.TelemetryFilter) ([]models.Task, int64, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+	args := withFilterArgs([]interface{}{projectId, fromDate, toDate}, filterArgs)
+
 	var count uint64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, fromDate, toDate).Scan(&count)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, args...).Scan(&count)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -58,8 +61,8 @@ func (e *taskRepository) FindAll(ctx context.Context, projectId uuid.UUID, fromD
 		orderBy = "recorded_at"
 	}
 
-	query := "SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? ORDER BY " + orderBy + " DESC LIMIT ? OFFSET ?"
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, fromDate, toDate, pageSize, offset)
+	query := "SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name, environment FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?" + filterSQL + " ORDER BY " + orderBy + " DESC LIMIT ? OFFSET ?"
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -69,7 +72,7 @@ func (e *taskRepository) FindAll(ctx context.Context, projectId uuid.UUID, fromD
 	for rows.Next() {
 		var t models.Task
 		var attributesJSON string
-		if err := rows.Scan(&t.Id, &t.ProjectId, &t.TaskName, &t.Duration, &t.RecordedAt, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName); err != nil {
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.TaskName, &t.Duration, &t.RecordedAt, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment); err != nil {
 			return nil, 0, err
 		}
 		if attributesJSON != "" && attributesJSON != "{}" {
@@ -83,10 +86,64 @@ func (e *taskRepository) FindAll(ctx context.Context, projectId uuid.UUID, fromD
 	return tasks, int64(count), nil
 }
 
-func (e *taskRepository) FindGroupedByTaskName(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string) ([]models.TaskStats, int64, error) {
+// Search returns the task runs matching the structured filters of the query, status codes don't apply to tasks
+func (e *taskRepository) Search(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, search models.TraceSearchQuery, page, pageSize int, orderBy string, sortDirection string) ([]models.Task, int64, error) {
+	searchSQL, searchArgs := traceSearchClause(search, "task_name", false)
+	args := withFilterArgs([]interface{}{projectId, fromDate, toDate}, searchArgs)
+
+	var count uint64
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?"+searchSQL, args...).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+
+	allowedOrderBy := map[string]bool{
+		"recorded_at": true,
+		"duration":    true,
+	}
+
+	if !allowedOrderBy[orderBy] {
+		orderBy = "recorded_at"
+	}
+	sortDir := "DESC"
+	if sortDirection == "asc" {
+		sortDir = "ASC"
+	}
+
+	query := "SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name, environment FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?" + searchSQL + " ORDER BY " + orderBy + " " + sortDir + " LIMIT ? OFFSET ?"
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, pageSize, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var t models.Task
+		var attributesJSON string
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.TaskName, &t.Duration, &t.RecordedAt, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment); err != nil {
+			return nil, 0, err
+		}
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &t.Attributes); err != nil {
+				t.Attributes = nil
+			}
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, int64(count), nil
+}
+
+func (e *taskRepository) FindGroupedByTaskName(ctx context.Context, projectId uuid.UUID, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string, filter models.TelemetryFilter) ([]models.TaskStats, int64, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+	args := withFilterArgs([]interface{}{projectId, fromDate, toDate}, filterArgs)
+
 	// Count unique task names
 	var count uint64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT uniq(task_name) FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, fromDate, toDate).Scan(&count)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT uniq(task_name) FROM tasks WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, args...).Scan(&count)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -122,12 +179,12 @@ func (e *taskRepository) FindGroupedByTaskName(ctx context.Context, projectId uu
 		avg(duration) as avg_duration,
 		max(recorded_at) as last_seen
 	FROM tasks
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY task_name
 	ORDER BY ` + orderExpr + ` ` + sortDir + `
 	LIMIT ? OFFSET ?`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, fromDate, toDate, pageSize, offset)
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -149,9 +206,12 @@ func (e *taskRepository) FindGroupedByTaskName(ctx context.Context, projectId uu
 	return stats, int64(count), nil
 }
 
-func (e *taskRepository) FindByTaskName(ctx context.Context, projectId uuid.UUID, taskName string, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string) ([]models.Task, int64, error) {
+func (e *taskRepository) FindByTaskName(ctx context.Context, projectId uuid.UUID, taskName string, fromDate, toDate time.Time, page, pageSize int, orderBy string, sortDirection string, filter models.TelemetryFilter) ([]models.Task, int64, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+	args := withFilterArgs([]interface{}{projectId, taskName, fromDate, toDate}, filterArgs)
+
 	var count uint64
-	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM tasks WHERE project_id = ? AND task_name = ? AND recorded_at >= ? AND recorded_at <= ?", projectId, taskName, fromDate, toDate).Scan(&count)
+	err := (*chdb.Conn).QueryRow(ctx, "SELECT count() FROM tasks WHERE project_id = ? AND task_name = ? AND recorded_at >= ? AND recorded_at <= ?"+filterSQL, args...).Scan(&count)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -173,8 +233,8 @@ func (e *taskRepository) FindByTaskName(ctx context.Context, projectId uuid.UUID
 		sortDir = "ASC"
 	}
 
-	query := "SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name FROM tasks WHERE project_id = ? AND task_name = ? AND recorded_at >= ? AND recorded_at <= ? ORDER BY " + orderBy + " " + sortDir + " LIMIT ? OFFSET ?"
-	rows, err := (*chdb.Conn).Query(ctx, query, projectId, taskName, fromDate, toDate, pageSize, offset)
+	query := "SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name, environment FROM tasks WHERE project_id = ? AND task_name = ? AND recorded_at >= ? AND recorded_at <= ?" + filterSQL + " ORDER BY " + orderBy + " " + sortDir + " LIMIT ? OFFSET ?"
+	rows, err := (*chdb.Conn).Query(ctx, query, append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -184,7 +244,7 @@ func (e *taskRepository) FindByTaskName(ctx context.Context, projectId uuid.UUID
 	for rows.Next() {
 		var t models.Task
 		var attributesJSON string
-		if err := rows.Scan(&t.Id, &t.ProjectId, &t.TaskName, &t.Duration, &t.RecordedAt, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName); err != nil {
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.TaskName, &t.Duration, &t.RecordedAt, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment); err != nil {
 			return nil, 0, err
 		}
 		if attributesJSON != "" && attributesJSON != "{}" {
@@ -200,7 +260,7 @@ func (e *taskRepository) FindByTaskName(ctx context.Context, projectId uuid.UUID
 
 // FindById returns a single task by ID
 func (e *taskRepository) FindById(ctx context.Context, projectId, taskId uuid.UUID) (*models.Task, error) {
-	query := `SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name
+	query := `SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name, environment, resource_attributes
 		FROM tasks
 		WHERE project_id = ? AND id = ?
 		LIMIT 1`
@@ -210,7 +270,7 @@ func (e *taskRepository) FindById(ctx context.Context, projectId, taskId uuid.UU
 
 	err := (*chdb.Conn).QueryRow(ctx, query, projectId, taskId).Scan(
 		&t.Id, &t.ProjectId, &t.TaskName, &t.Duration, &t.RecordedAt,
-		&t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName)
+		&t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment, &t.ResourceAttributes)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -228,6 +288,41 @@ func (e *taskRepository) FindById(ctx context.Context, projectId, taskId uuid.UU
 	return &t, nil
 }
 
+// FindByTraceIdInProjects returns the tasks recorded for a trace id in any of the projects
+func (e *taskRepository) FindByTraceIdInProjects(ctx context.Context, projectIds []uuid.UUID, traceId uuid.UUID) ([]models.Task, error) {
+	if len(projectIds) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id, project_id, task_name, duration, recorded_at, client_ip, attributes, app_version, server_name, environment, resource_attributes
+		FROM tasks
+		WHERE project_id IN (?) AND id = ?
+		ORDER BY recorded_at ASC`
+
+	rows, err := (*chdb.Conn).Query(ctx, query, projectIds, traceId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var t models.Task
+		var attributesJSON string
+		if err := rows.Scan(&t.Id, &t.ProjectId, &t.TaskName, &t.Duration, &t.RecordedAt, &t.ClientIP, &attributesJSON, &t.AppVersion, &t.ServerName, &t.Environment, &t.ResourceAttributes); err != nil {
+			return nil, err
+		}
+		if attributesJSON != "" && attributesJSON != "{}" {
+			if err := json.Unmarshal([]byte(attributesJSON), &t.Attributes); err != nil {
+				t.Attributes = nil
+			}
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
 // CountByHour returns task counts grouped by hour
 func (e *taskRepository) CountByHour(ctx context.Context, projectId uuid.UUID, start, end time.Time) ([]models.TimeSeriesPoint, error) {
 	query := `SELECT
@@ -285,16 +380,18 @@ func (e *taskRepository) AvgDurationByHour(ctx context.Context, projectId uuid.U
 }
 
 // CountByInterval returns task counts grouped by configurable interval in minutes
-func (e *taskRepository) CountByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int) ([]models.TimeSeriesPoint, error) {
+func (e *taskRepository) CountByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.TimeSeriesPoint, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
 	query := `SELECT
 		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
 		toFloat64(count()) as count
 	FROM tasks
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY bucket
 	ORDER BY bucket ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, intervalMinutes, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -313,16 +410,18 @@ func (e *taskRepository) CountByInterval(ctx context.Context, projectId uuid.UUI
 }
 
 // AvgDurationByInterval returns average duration in ms grouped by configurable interval
-func (e *taskRepository) AvgDurationByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int) ([]models.TimeSeriesPoint, error) {
+func (e *taskRepository) AvgDurationByInterval(ctx context.Context, projectId uuid.UUID, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.TimeSeriesPoint, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
 	query := `SELECT
 		toStartOfInterval(recorded_at, INTERVAL ? MINUTE) as bucket,
 		avg(duration) / 1000000 as avg_duration_ms
 	FROM tasks
-	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?
+	WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL + `
 	GROUP BY bucket
 	ORDER BY bucket ASC`
 
-	rows, err := (*chdb.Conn).Query(ctx, query, intervalMinutes, projectId, start, end)
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs([]interface{}{intervalMinutes, projectId, start, end}, filterArgs)...)
 	if err != nil {
 		return nil, err
 	}
@@ -378,13 +477,15 @@ func (e *taskRepository) FindWorstTasks(ctx context.Context, projectId uuid.UUID
 }
 
 // GetTaskStats returns aggregate statistics for a specific task
-func (e *taskRepository) GetTaskStats(ctx context.Context, projectId uuid.UUID, taskName string, start, end time.Time) (*models.TaskDetailStats, error) {
+func (e *taskRepository) GetTaskStats(ctx context.Context, projectId uuid.UUID, taskName string, start, end time.Time, filter models.TelemetryFilter) (*models.TaskDetailStats, error) {
 	// Calculate time range duration for throughput calculation
 	durationMinutes := end.Sub(start).Minutes()
 	if durationMinutes < 1 {
 		durationMinutes = 1
 	}
 
+	filterSQL, filterArgs := traceFilterClause(filter, "")
+
 	query := `SELECT
 		count() as count,
 		avg(duration) / 1000000 as avg_duration_ms,
@@ -392,12 +493,12 @@ func (e *taskRepository) GetTaskStats(ctx context.Context, projectId uuid.UUID,
 		quantile(0.95)(duration) / 1000000 as p95_duration_ms,
 		quantile(0.99)(duration) / 1000000 as p99_duration_ms
 	FROM tasks
-	WHERE project_id = ? AND task_name = ? AND recorded_at >= ? AND recorded_at <= ?`
+	WHERE project_id = ? AND task_name = ? AND recorded_at >= ? AND recorded_at <= ?` + filterSQL
 
 	var stats models.TaskDetailStats
 	var count uint64
 
-	err := (*chdb.Conn).QueryRow(ctx, query, projectId, taskName, start, end).Scan(
+	err := (*chdb.Conn).QueryRow(ctx, query, withFilterArgs([]interface{}{projectId, taskName, start, end}, filterArgs)...).Scan(
 		&count,
 		&stats.AvgDuration,
 		&stats.MedianDuration,
@@ -415,4 +516,48 @@ func (e *taskRepository) GetTaskStats(ctx context.Context, projectId uuid.UUID,
 	return &stats, nil
 }
 
+// PerformanceByInterval returns the percentiles, throughput and error rate of a single task grouped by configurable interval.
+// A run counts as failed when it reported an exception.
+func (e *taskRepository) PerformanceByInterval(ctx context.Context, projectId uuid.UUID, taskName string, start, end time.Time, intervalMinutes int, filter models.TelemetryFilter) ([]models.PerformanceSeriesPoint, error) {
+	filterSQL, filterArgs := traceFilterClause(filter, "t")
+
+	query := `SELECT
+		toStartOfInterval(t.recorded_at, INTERVAL ? MINUTE) as bucket,
+		count() as count,
+		quantile(0.5)(t.duration) / 1000000 as p50_duration_ms,
+		quantile(0.95)(t.duration) / 1000000 as p95_duration_ms,
+		quantile(0.99)(t.duration) / 1000000 as p99_duration_ms,
+		countIf(f.failed = 1) * 100.0 / count() as error_rate
+	FROM tasks t
+	LEFT JOIN (
+		SELECT DISTINCT assumeNotNull(trace_id) as trace_id, 1 as failed
+		FROM exception_stack_traces
+		WHERE project_id = ? AND recorded_at >= ? AND recorded_at <= ? AND trace_type = 'task' AND is_message = 0 AND trace_id IS NOT NULL
+	) f ON t.id = f.trace_id
+	WHERE t.project_id = ? AND t.task_name = ? AND t.recorded_at >= ? AND t.recorded_at <= ?` + filterSQL + `
+	GROUP BY bucket
+	ORDER BY bucket ASC`
+
+	// exceptions are recorded while the task runs, after it started
+	args := []interface{}{intervalMinutes, projectId, start, end.Add(time.Hour), projectId, taskName, start, end}
+
+	rows, err := (*chdb.Conn).Query(ctx, query, withFilterArgs(args, filterArgs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []models.PerformanceSeriesPoint
+	for rows.Next() {
+		var p models.PerformanceSeriesPoint
+		if err := rows.Scan(&p.Timestamp, &p.Count, &p.P50Duration, &p.P95Duration, &p.P99Duration, &p.ErrorRate); err != nil {
+			return nil, err
+		}
+		p.Throughput = float64(p.Count) / float64(intervalMinutes)
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
 var TaskRepository = taskRepository{}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"backend/app/models"
+	"sort"
+)
+
+// filterClause returns the extra WHERE conditions (starting with " AND") and their args for a telemetry filter.
+// alias prefixes the column names when the query joins other tables, e.g. "e".
+func filterClause(filter models.TelemetryFilter, alias string) (string, []interface{}) {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+
+	clause := ""
+	var args []interface{}
+	if filter.Environment != "" {
+		clause += " AND " + prefix + "environment = ?"
+		args = append(args, filter.Environment)
+	}
+	return clause, args
+}
+
+// traceFilterClause extends filterClause with the resource attribute conditions,
+// only endpoints and tasks store resource attributes.
+func traceFilterClause(filter models.TelemetryFilter, alias string) (string, []interface{}) {
+	clause, args := filterClause(filter, alias)
+
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+
+	// sorted so the generated query is stable for the same filter
+	keys := make([]string, 0, len(filter.ResourceAttributes))
+	for key := range filter.ResourceAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		clause += " AND " + prefix + "resource_attributes[?] = ?"
+		args = append(args, key, filter.ResourceAttributes[key])
+	}
+	return clause, args
+}
+
+// resourceAttributesOrEmpty avoids inserting a nil map into a Map column
+func resourceAttributesOrEmpty(attributes map[string]string) map[string]string {
+	if attributes == nil {
+		return map[string]string{}
+	}
+	return attributes
+}
+
+// withFilterArgs appends the filter args to the args of the conditions preceding the filter clause
+func withFilterArgs(args []interface{}, filterArgs []interface{}) []interface{} {
+	return append(append([]interface{}{}, args...), filterArgs...)
+}
+
+// userIdentityExpr returns the user id of a row from the attribute configured in the project settings (its only arg),
+// falling back to OTLP's enduser.id. Rows without either are NULL so uniq skips them.
+func userIdentityExpr(alias string) string {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+	return "coalesce(nullIf(" + prefix + "attributes_map[?], ''), nullIf(" + prefix + "attributes_map['enduser.id'], ''))"
+}
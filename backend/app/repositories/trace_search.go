@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"backend/app/models"
+	"strconv"
+	"strings"
+)
+
+// traceSearchClause returns the extra WHERE conditions (starting with " AND") and their args for a trace search.
+// nameColumn is endpoint or task_name, status code ranges only apply when the table has a status_code column.
+// Attribute filters run against the attributes_map column materialized from the JSON attributes.
+func traceSearchClause(query models.TraceSearchQuery, nameColumn string, hasStatusCode bool) (string, []interface{}) {
+	clause, args := traceFilterClause(query.TelemetryFilter, "")
+
+	if query.Name != "" {
+		clause += " AND " + nameColumn + " = ?"
+		args = append(args, query.Name)
+	}
+
+	if hasStatusCode && len(query.StatusCodes) > 0 {
+		ranges := make([]string, 0, len(query.StatusCodes))
+		for _, r := range query.StatusCodes {
+			ranges = append(ranges, "status_code BETWEEN ? AND ?")
+			args = append(args, r.Min, r.Max)
+		}
+		clause += " AND (" + strings.Join(ranges, " OR ") + ")"
+	}
+
+	if query.MinDuration > 0 {
+		clause += " AND duration >= ?"
+		args = append(args, int64(query.MinDuration))
+	}
+	if query.MaxDuration > 0 {
+		clause += " AND duration <= ?"
+		args = append(args, int64(query.MaxDuration))
+	}
+
+	if query.ServerName != "" {
+		clause += " AND server_name = ?"
+		args = append(args, query.ServerName)
+	}
+	if query.AppVersion != "" {
+		clause += " AND app_version = ?"
+		args = append(args, query.AppVersion)
+	}
+	if query.ClientIP != "" {
+		clause += " AND client_ip = ?"
+		args = append(args, query.ClientIP)
+	}
+
+	for _, f := range query.Attributes {
+		condition, conditionArgs := attributeFilterCondition(f)
+		if condition == "" {
+			continue
+		}
+		clause += " AND " + condition
+		args = append(args, conditionArgs...)
+	}
+
+	return clause, args
+}
+
+// attributeFilterCondition translates one attribute filter, filters are validated by services.ValidateAttributeFilter
+func attributeFilterCondition(f models.AttributeFilter) (string, []interface{}) {
+	switch f.Operator {
+	case models.AttributeOperatorEquals:
+		return "attributes_map[?] = ?", []interface{}{f.Key, f.Value}
+	case models.AttributeOperatorNotEquals:
+		return "attributes_map[?] != ?", []interface{}{f.Key, f.Value}
+	case models.AttributeOperatorContains:
+		return "positionCaseInsensitive(attributes_map[?], ?) > 0", []interface{}{f.Key, f.Value}
+	case models.AttributeOperatorNotContains:
+		return "positionCaseInsensitive(attributes_map[?], ?) = 0", []interface{}{f.Key, f.Value}
+	case models.AttributeOperatorExists:
+		return "mapContains(attributes_map, ?)", []interface{}{f.Key}
+	case models.AttributeOperatorNotExists:
+		return "NOT mapContains(attributes_map, ?)", []interface{}{f.Key}
+	}
+
+	comparisons := map[string]string{
+		models.AttributeOperatorGreaterThan:    ">",
+		models.AttributeOperatorGreaterOrEqual: ">=",
+		models.AttributeOperatorLessThan:       "<",
+		models.AttributeOperatorLessOrEqual:    "<=",
+	}
+	operator, ok := comparisons[f.Operator]
+	if !ok {
+		return "", nil
+	}
+	value, err := strconv.ParseFloat(f.Value, 64)
+	if err != nil {
+		return "", nil
+	}
+	return "toFloat64OrNull(attributes_map[?]) " + operator + " ?", []interface{}{f.Key, value}
+}
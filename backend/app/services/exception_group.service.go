@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CanonicalExceptionHash returns the group an exception hash was merged into, or the hash itself when it wasn't merged
+func CanonicalExceptionHash(aliases map[string]string, hash string) string {
+	if canonical, ok := aliases[hash]; ok {
+		return canonical
+	}
+	return hash
+}
+
+// ExceptionGroupMembers returns the canonical hash of a group followed by the sorted hashes merged into it
+func ExceptionGroupMembers(aliases map[string]string, hash string) []string {
+	canonical := CanonicalExceptionHash(aliases, hash)
+	var merged []string
+	for alias, target := range aliases {
+		if target == canonical {
+			merged = append(merged, alias)
+		}
+	}
+	sort.Strings(merged)
+	return append([]string{canonical}, merged...)
+}
+
+// MergeExceptionGroups returns the aliases to store when merging the groups of hashes into the group of target.
+// Groups that were merged before move over with all their members so aliases always point at a canonical hash.
+func MergeExceptionGroups(aliases map[string]string, target string, hashes []string) (map[string]string, error) {
+	canonical := CanonicalExceptionHash(aliases, target)
+	merged := map[string]string{}
+	for _, hash := range hashes {
+		if hash == "" {
+			return nil, fmt.Errorf("exception hash is required")
+		}
+		if CanonicalExceptionHash(aliases, hash) == canonical {
+			continue
+		}
+		for _, member := range ExceptionGroupMembers(aliases, hash) {
+			merged[member] = canonical
+		}
+	}
+	return merged, nil
+}
+
+// UnmergeExceptionHashes returns the aliases to delete to split hashes out of their groups,
+// unmerging a canonical hash splits every member out of its group
+func UnmergeExceptionHashes(aliases map[string]string, hashes []string) []string {
+	removed := map[string]bool{}
+	for _, hash := range hashes {
+		if _, ok := aliases[hash]; ok {
+			removed[hash] = true
+			continue
+		}
+		for _, member := range ExceptionGroupMembers(aliases, hash)[1:] {
+			removed[member] = true
+		}
+	}
+
+	result := make([]string, 0, len(removed))
+	for hash := range removed {
+		result = append(result, hash)
+	}
+	sort.Strings(result)
+	return result
+}
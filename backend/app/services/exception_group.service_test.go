@@ -0,0 +1,60 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeExceptionGroups(t *testing.T) {
+	aliases := map[string]string{"b": "a", "d": "c"}
+
+	tests := []struct {
+		name     string
+		target   string
+		hashes   []string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"new hash", "a", []string{"e"}, map[string]string{"e": "a"}, false},
+		{"target is an alias", "b", []string{"e"}, map[string]string{"e": "a"}, false},
+		{"merged group moves with its members", "a", []string{"c"}, map[string]string{"c": "a", "d": "a"}, false},
+		{"already in the group", "a", []string{"a", "b"}, map[string]string{}, false},
+		{"empty hash", "a", []string{""}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeExceptionGroups(aliases, tt.target, tt.hashes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergeExceptionGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("MergeExceptionGroups() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnmergeExceptionHashes(t *testing.T) {
+	aliases := map[string]string{"b": "a", "c": "a", "e": "d"}
+
+	tests := []struct {
+		name     string
+		hashes   []string
+		expected []string
+	}{
+		{"alias", []string{"b"}, []string{"b"}},
+		{"canonical splits every member", []string{"a"}, []string{"b", "c"}},
+		{"mixed", []string{"c", "d"}, []string{"c", "e"}},
+		{"not merged", []string{"x"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnmergeExceptionHashes(aliases, tt.hashes)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("UnmergeExceptionHashes(%v) = %v, want %v", tt.hashes, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var (
+	errorMessageRe = regexp.MustCompile(`(?m)^(\*?[\w.]+):\s*.+`)
+	absolutePathRe = regexp.MustCompile(`/[^\s:]+/([^/\s:]+:\d+)`)
+	versionRe      = regexp.MustCompile(`@v[\d.]+`)
+	hexRe          = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	uuidRe         = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	largeNumberRe  = regexp.MustCompile(`(^|[^:\d])(\d{5,})($|[^\d])`)
+	emailRe        = regexp.MustCompile(`[\w.\-]+@[\w.\-]+\.\w+`)
+	ipRe           = regexp.MustCompile(`\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}(:\d+)?`)
+	goroutineRe    = regexp.MustCompile(`goroutine \d+`)
+	spacesRe       = regexp.MustCompile(`[ \t]+`)
+	newlinesRe     = regexp.MustCompile(`\n+`)
+)
+
+// ComputeExceptionHash groups exceptions by their stack trace with messages, paths, ids and addresses normalized away,
+// messages are hashed as-is
+func ComputeExceptionHash(stackTrace string, isMessage bool) string {
+	normalized := stackTrace
+
+	if !isMessage {
+		normalized = errorMessageRe.ReplaceAllString(normalized, "$1")
+		normalized = absolutePathRe.ReplaceAllString(normalized, "$1")
+		normalized = versionRe.ReplaceAllString(normalized, "")
+		normalized = hexRe.ReplaceAllString(normalized, "<hex>")
+		normalized = uuidRe.ReplaceAllString(normalized, "<uuid>")
+		normalized = largeNumberRe.ReplaceAllString(normalized, "${1}<id>${3}")
+		normalized = emailRe.ReplaceAllString(normalized, "<email>")
+		normalized = ipRe.ReplaceAllString(normalized, "<ip>")
+		normalized = goroutineRe.ReplaceAllString(normalized, "goroutine <n>")
+		normalized = spacesRe.ReplaceAllString(normalized, " ")
+		normalized = newlinesRe.ReplaceAllString(normalized, "\n")
+	}
+
+	normalized = strings.TrimSpace(normalized)
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])[:16]
+}
@@ -0,0 +1,27 @@
+package services
+
+import "backend/app/models"
+
+// HealthStatus compares a metric value against the project's thresholds for its type.
+// Metric types without a configured threshold are informational and always healthy.
+func HealthStatus(value float64, metricType string, thresholds models.HealthThresholdProfile) string {
+	levels, ok := thresholds[metricType]
+	if !ok {
+		return models.HealthStatusHealthy
+	}
+	if models.IsLowerWorseHealthMetric(metricType) {
+		// Lower is worse (e.g. less traffic may indicate issues)
+		if value < levels.Critical {
+			return models.HealthStatusCritical
+		} else if value < levels.Warning {
+			return models.HealthStatusWarning
+		}
+		return models.HealthStatusHealthy
+	}
+	if value > levels.Critical {
+		return models.HealthStatusCritical
+	} else if value > levels.Warning {
+		return models.HealthStatusWarning
+	}
+	return models.HealthStatusHealthy
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"backend/app/models"
+	"testing"
+)
+
+func TestHealthStatus(t *testing.T) {
+	defaults := models.NewHealthThresholdProfile(nil)
+	custom := models.NewHealthThresholdProfile([]*models.HealthThreshold{
+		{MetricType: "requests", Warning: 1000, Critical: 200},
+		{MetricType: "response_time", Warning: 1000, Critical: 2000},
+		{MetricType: "gc_pause", Warning: 5, Critical: 10},
+	})
+
+	tests := []struct {
+		name       string
+		profile    models.HealthThresholdProfile
+		metricType string
+		value      float64
+		expected   string
+	}{
+		// lower is worse, the status changes once the value drops below a level
+		{"requests above warning", defaults, "requests", 101, models.HealthStatusHealthy},
+		{"requests at warning", defaults, "requests", 100, models.HealthStatusHealthy},
+		{"requests below warning", defaults, "requests", 99, models.HealthStatusWarning},
+		{"requests at critical", defaults, "requests", 10, models.HealthStatusWarning},
+		{"requests below critical", defaults, "requests", 9, models.HealthStatusCritical},
+
+		// higher is worse, the status changes once the value exceeds a level
+		{"exceptions at warning", defaults, "exceptions", 10, models.HealthStatusHealthy},
+		{"exceptions above warning", defaults, "exceptions", 11, models.HealthStatusWarning},
+		{"exceptions at critical", defaults, "exceptions", 50, models.HealthStatusWarning},
+		{"exceptions above critical", defaults, "exceptions", 51, models.HealthStatusCritical},
+		{"response time at warning", defaults, "response_time", 200, models.HealthStatusHealthy},
+		{"response time above critical", defaults, "response_time", 501, models.HealthStatusCritical},
+		{"error rate above warning", defaults, "error_rate", 2.1, models.HealthStatusWarning},
+		{"error rate above critical", defaults, "error_rate", 5.1, models.HealthStatusCritical},
+		{"cpu at critical", defaults, "cpu", 90, models.HealthStatusWarning},
+		{"cpu above critical", defaults, "cpu", 90.5, models.HealthStatusCritical},
+		{"memory above warning", defaults, "memory", 701, models.HealthStatusWarning},
+		{"go routines above critical", defaults, "go_routines", 10001, models.HealthStatusCritical},
+		{"heap objects at warning", defaults, "heap_objects", 500000, models.HealthStatusHealthy},
+
+		// informational metric types have no default
+		{"memory total", defaults, "memory_total", 1e9, models.HealthStatusHealthy},
+		{"num gc", defaults, "num_gc", 1e9, models.HealthStatusHealthy},
+		{"gc pause without override", defaults, "gc_pause", 1e9, models.HealthStatusHealthy},
+		{"unknown metric type", defaults, "disk", 1e9, models.HealthStatusHealthy},
+
+		// overrides replace the defaults of their metric type only
+		{"custom requests below warning", custom, "requests", 500, models.HealthStatusWarning},
+		{"custom requests below critical", custom, "requests", 199, models.HealthStatusCritical},
+		{"custom response time above default critical", custom, "response_time", 600, models.HealthStatusHealthy},
+		{"custom response time above critical", custom, "response_time", 2001, models.HealthStatusCritical},
+		{"custom gc pause above warning", custom, "gc_pause", 6, models.HealthStatusWarning},
+		{"default kept next to overrides", custom, "cpu", 75, models.HealthStatusWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HealthStatus(tt.value, tt.metricType, tt.profile); got != tt.expected {
+				t.Errorf("HealthStatus(%v, %q) = %q, want %q", tt.value, tt.metricType, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"backend/app/models"
+	"math"
+	"time"
+)
+
+// LatencyBucketIndex returns the log-scaled bucket of a duration, the latency repository computes the same in ClickHouse
+func LatencyBucketIndex(d time.Duration) int {
+	if d < models.LatencyBucketBase {
+		d = models.LatencyBucketBase
+	}
+	index := int(math.Floor(math.Log2(float64(d)/float64(models.LatencyBucketBase)) * models.LatencyBucketsPerDoubling))
+	return min(index, models.LatencyMaxBucket)
+}
+
+// LatencyBucketBounds returns the lower and upper bound of a bucket in milliseconds, the first bucket starts at zero
+func LatencyBucketBounds(index int) (float64, float64) {
+	baseMs := float64(models.LatencyBucketBase) / float64(time.Millisecond)
+	upper := baseMs * math.Pow(2, float64(index+1)/models.LatencyBucketsPerDoubling)
+	if index == 0 {
+		return 0, upper
+	}
+	return baseMs * math.Pow(2, float64(index)/models.LatencyBucketsPerDoubling), upper
+}
+
+// BuildLatencyHistogram turns bucket counts into a contiguous histogram, empty buckets between the fastest and slowest are kept
+func BuildLatencyHistogram(counts []models.LatencyBucketCount) models.LatencyHistogram {
+	histogram := models.LatencyHistogram{Buckets: []models.LatencyHistogramBucket{}}
+	if len(counts) == 0 {
+		return histogram
+	}
+
+	first, last := bucketRange(len(counts), func(i int) int { return counts[i].Bucket })
+	histogram.Buckets = latencyBuckets(first, last)
+	for _, c := range counts {
+		histogram.Buckets[c.Bucket-first].Count += c.Count
+		histogram.Total += c.Count
+	}
+	return histogram
+}
+
+// maxLatencyHeatmapRows caps the rows of a heatmap, a longer range keeps the intervals closest to its end
+const maxLatencyHeatmapRows = 2000
+
+// BuildLatencyHeatmap arranges the cells into a matrix with a row for every interval between start and end,
+// intervals are aligned the same way as toStartOfInterval
+func BuildLatencyHeatmap(cells []models.LatencyHeatmapCell, start, end time.Time, intervalMinutes int) models.LatencyHeatmap {
+	heatmap := models.LatencyHeatmap{
+		IntervalMinutes: intervalMinutes,
+		Timestamps:      []time.Time{},
+		Buckets:         []models.LatencyHistogramBucket{},
+		Counts:          [][]uint64{},
+	}
+	if len(cells) == 0 || intervalMinutes <= 0 {
+		return heatmap
+	}
+
+	first, last := bucketRange(len(cells), func(i int) int { return cells[i].Bucket })
+	heatmap.Buckets = latencyBuckets(first, last)
+
+	interval := time.Duration(intervalMinutes) * time.Minute
+	firstRow := start.UTC().Truncate(interval)
+	if earliest := end.UTC().Truncate(interval).Add(-(maxLatencyHeatmapRows - 1) * interval); firstRow.Before(earliest) {
+		firstRow = earliest
+	}
+	rows := make(map[int64]int)
+	for t := firstRow; !t.After(end); t = t.Add(interval) {
+		rows[t.Unix()] = len(heatmap.Timestamps)
+		heatmap.Timestamps = append(heatmap.Timestamps, t)
+		heatmap.Counts = append(heatmap.Counts, make([]uint64, len(heatmap.Buckets)))
+	}
+
+	for _, c := range cells {
+		row, ok := rows[c.Timestamp.UTC().Truncate(interval).Unix()]
+		if !ok {
+			continue
+		}
+		heatmap.Counts[row][c.Bucket-first] += c.Count
+		heatmap.Buckets[c.Bucket-first].Count += c.Count
+	}
+	return heatmap
+}
+
+func bucketRange(n int, bucket func(i int) int) (int, int) {
+	first, last := bucket(0), bucket(0)
+	for i := 1; i < n; i++ {
+		first = min(first, bucket(i))
+		last = max(last, bucket(i))
+	}
+	return first, last
+}
+
+func latencyBuckets(first, last int) []models.LatencyHistogramBucket {
+	buckets := make([]models.LatencyHistogramBucket, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		lower, upper := LatencyBucketBounds(i)
+		buckets = append(buckets, models.LatencyHistogramBucket{LowerBoundMs: lower, UpperBoundMs: upper})
+	}
+	return buckets
+}
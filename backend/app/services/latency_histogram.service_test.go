@@ -0,0 +1,104 @@
+package services
+
+import (
+	"backend/app/models"
+	"testing"
+	"time"
+)
+
+func TestLatencyBucketIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		expected int
+	}{
+		{"below the base", 200 * time.Microsecond, 0},
+		{"base", time.Millisecond, 0},
+		{"half doubling", 1500 * time.Microsecond, 1},
+		{"one doubling", 2 * time.Millisecond, 2},
+		{"one second", time.Second, 19},
+		{"clamped", time.Hour, models.LatencyMaxBucket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LatencyBucketIndex(tt.duration); got != tt.expected {
+				t.Errorf("LatencyBucketIndex(%v) = %d, want %d", tt.duration, got, tt.expected)
+			}
+			lower, upper := LatencyBucketBounds(tt.expected)
+			ms := float64(tt.duration) / float64(time.Millisecond)
+			if tt.expected != models.LatencyMaxBucket && (ms < lower || ms >= upper) {
+				t.Errorf("%v is outside bucket %d [%v, %v)", tt.duration, tt.expected, lower, upper)
+			}
+		})
+	}
+}
+
+func TestBuildLatencyHistogramFillsGaps(t *testing.T) {
+	histogram := BuildLatencyHistogram([]models.LatencyBucketCount{
+		{Bucket: 6, Count: 3},
+		{Bucket: 4, Count: 10},
+	})
+
+	if histogram.Total != 13 {
+		t.Errorf("Total = %d, want 13", histogram.Total)
+	}
+	if len(histogram.Buckets) != 3 {
+		t.Fatalf("got %d buckets, want 3", len(histogram.Buckets))
+	}
+	counts := []uint64{10, 0, 3}
+	for i, b := range histogram.Buckets {
+		if b.Count != counts[i] {
+			t.Errorf("bucket %d count = %d, want %d", i, b.Count, counts[i])
+		}
+	}
+	if histogram.Buckets[0].LowerBoundMs != 4 || histogram.Buckets[2].UpperBoundMs < 11.3 || histogram.Buckets[2].UpperBoundMs > 11.4 {
+		t.Errorf("unexpected bounds %+v", histogram.Buckets)
+	}
+}
+
+func TestBuildLatencyHeatmap(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 2, 0, 0, time.UTC)
+	end := start.Add(14 * time.Minute)
+	cells := []models.LatencyHeatmapCell{
+		{Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Bucket: 10, Count: 5},
+		{Timestamp: time.Date(2025, 1, 1, 12, 10, 0, 0, time.UTC), Bucket: 12, Count: 2},
+		{Timestamp: time.Date(2025, 1, 1, 12, 10, 0, 0, time.UTC), Bucket: 10, Count: 1},
+	}
+
+	heatmap := BuildLatencyHeatmap(cells, start, end, 5)
+
+	if len(heatmap.Timestamps) != 4 {
+		t.Fatalf("got %d rows, want 4 (12:00 to 12:15)", len(heatmap.Timestamps))
+	}
+	if len(heatmap.Buckets) != 3 {
+		t.Fatalf("got %d buckets, want 3", len(heatmap.Buckets))
+	}
+	if heatmap.Counts[0][0] != 5 || heatmap.Counts[2][2] != 2 || heatmap.Counts[2][0] != 1 || heatmap.Counts[1][0] != 0 {
+		t.Errorf("unexpected counts %v", heatmap.Counts)
+	}
+	if heatmap.Buckets[0].Count != 6 {
+		t.Errorf("bucket total = %d, want 6", heatmap.Buckets[0].Count)
+	}
+}
+
+func TestBuildLatencyHeatmapCapsRows(t *testing.T) {
+	end := time.Date(2025, 1, 1, 12, 2, 0, 0, time.UTC)
+	cells := []models.LatencyHeatmapCell{
+		{Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Bucket: 10, Count: 3},
+	}
+
+	// a zero start would otherwise fill every minute since year 1
+	heatmap := BuildLatencyHeatmap(cells, time.Time{}, end, 1)
+
+	if len(heatmap.Timestamps) != maxLatencyHeatmapRows {
+		t.Fatalf("got %d rows, want %d", len(heatmap.Timestamps), maxLatencyHeatmapRows)
+	}
+	last := len(heatmap.Timestamps) - 1
+	if !heatmap.Timestamps[last].Equal(time.Date(2025, 1, 1, 12, 2, 0, 0, time.UTC)) {
+		t.Errorf("last row = %v, want 12:02", heatmap.Timestamps[last])
+	}
+	if heatmap.Counts[last-2][0] != 3 {
+		t.Errorf("unexpected counts %v", heatmap.Counts[last-2:])
+	}
+}
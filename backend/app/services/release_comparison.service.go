@@ -0,0 +1,95 @@
+package services
+
+import (
+	"backend/app/models"
+	"sort"
+)
+
+// increasedExceptionFactor is how much more often an exception has to occur in the target version to be reported as increased
+const increasedExceptionFactor = 1.5
+
+// BuildReleaseComparison computes per-endpoint deltas and exception changes between two versions.
+// Exception counts are normalized by each version's request volume when both versions served traffic,
+// so a release that has only been live for an hour isn't compared by raw counts against one live for a week.
+func BuildReleaseComparison(baseVersion, targetVersion string, endpointStats []models.VersionEndpointStats, exceptionCounts []models.VersionExceptionCounts) *models.ReleaseComparison {
+	comparison := &models.ReleaseComparison{
+		BaseVersion:         baseVersion,
+		TargetVersion:       targetVersion,
+		Endpoints:           []models.EndpointReleaseDelta{},
+		NewExceptions:       []models.VersionExceptionCounts{},
+		GoneExceptions:      []models.VersionExceptionCounts{},
+		IncreasedExceptions: []models.VersionExceptionCounts{},
+	}
+
+	deltas := make(map[string]*models.EndpointReleaseDelta)
+	var baseTraffic, targetTraffic uint64
+	for i := range endpointStats {
+		s := &endpointStats[i]
+		d, ok := deltas[s.Endpoint]
+		if !ok {
+			d = &models.EndpointReleaseDelta{Endpoint: s.Endpoint}
+			deltas[s.Endpoint] = d
+		}
+		switch s.AppVersion {
+		case baseVersion:
+			d.Base = s
+			baseTraffic += s.Count
+		case targetVersion:
+			d.Target = s
+			targetTraffic += s.Count
+		}
+	}
+
+	for _, d := range deltas {
+		switch {
+		case d.Base == nil:
+			d.Status = models.ReleaseDeltaNew
+			d.CountDelta = int64(d.Target.Count)
+		case d.Target == nil:
+			d.Status = models.ReleaseDeltaRemoved
+			d.CountDelta = -int64(d.Base.Count)
+		default:
+			d.Status = models.ReleaseDeltaChanged
+			d.CountDelta = int64(d.Target.Count) - int64(d.Base.Count)
+			d.P50Delta = d.Target.P50Duration - d.Base.P50Duration
+			d.P95Delta = d.Target.P95Duration - d.Base.P95Duration
+			d.P99Delta = d.Target.P99Duration - d.Base.P99Duration
+			d.ErrorRateDelta = d.Target.ErrorRate - d.Base.ErrorRate
+		}
+		comparison.Endpoints = append(comparison.Endpoints, *d)
+	}
+
+	// Biggest p95 regressions first, endpoints only present in one version last
+	sort.Slice(comparison.Endpoints, func(i, j int) bool {
+		a, b := comparison.Endpoints[i], comparison.Endpoints[j]
+		aChanged, bChanged := a.Status == models.ReleaseDeltaChanged, b.Status == models.ReleaseDeltaChanged
+		if aChanged != bChanged {
+			return aChanged
+		}
+		if a.P95Delta != b.P95Delta {
+			return a.P95Delta > b.P95Delta
+		}
+		return a.Endpoint < b.Endpoint
+	})
+
+	normalize := baseTraffic > 0 && targetTraffic > 0
+	for _, c := range exceptionCounts {
+		switch {
+		case c.BaseCount == 0 && c.TargetCount > 0:
+			comparison.NewExceptions = append(comparison.NewExceptions, c)
+		case c.BaseCount > 0 && c.TargetCount == 0:
+			comparison.GoneExceptions = append(comparison.GoneExceptions, c)
+		case c.BaseCount > 0 && c.TargetCount > 0:
+			baseRate, targetRate := float64(c.BaseCount), float64(c.TargetCount)
+			if normalize {
+				baseRate /= float64(baseTraffic)
+				targetRate /= float64(targetTraffic)
+			}
+			if targetRate > baseRate*increasedExceptionFactor {
+				comparison.IncreasedExceptions = append(comparison.IncreasedExceptions, c)
+			}
+		}
+	}
+
+	return comparison
+}
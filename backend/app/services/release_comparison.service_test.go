@@ -0,0 +1,83 @@
+package services
+
+import (
+	"backend/app/models"
+	"testing"
+	"time"
+)
+
+func TestBuildReleaseComparison(t *testing.T) {
+	endpointStats := []models.VersionEndpointStats{
+		{AppVersion: "1.0", Endpoint: "GET /users", Count: 100, P95Duration: 100 * time.Millisecond},
+		{AppVersion: "1.1", Endpoint: "GET /users", Count: 100, P95Duration: 300 * time.Millisecond},
+		{AppVersion: "1.0", Endpoint: "GET /health", Count: 50, P95Duration: 5 * time.Millisecond},
+		{AppVersion: "1.1", Endpoint: "GET /health", Count: 50, P95Duration: 5 * time.Millisecond},
+		{AppVersion: "1.0", Endpoint: "GET /legacy", Count: 10},
+		{AppVersion: "1.1", Endpoint: "POST /orders", Count: 20},
+	}
+
+	comparison := BuildReleaseComparison("1.0", "1.1", endpointStats, nil)
+
+	expected := []struct {
+		endpoint   string
+		status     string
+		p95Delta   time.Duration
+		countDelta int64
+	}{
+		// regressed endpoints first, then unchanged ones, then endpoints present in one version only
+		{"GET /users", models.ReleaseDeltaChanged, 200 * time.Millisecond, 0},
+		{"GET /health", models.ReleaseDeltaChanged, 0, 0},
+		{"GET /legacy", models.ReleaseDeltaRemoved, 0, -10},
+		{"POST /orders", models.ReleaseDeltaNew, 0, 20},
+	}
+	if len(comparison.Endpoints) != len(expected) {
+		t.Fatalf("BuildReleaseComparison() returned %d endpoints, want %d", len(comparison.Endpoints), len(expected))
+	}
+	for i, e := range expected {
+		d := comparison.Endpoints[i]
+		if d.Endpoint != e.endpoint || d.Status != e.status || d.P95Delta != e.p95Delta || d.CountDelta != e.countDelta {
+			t.Errorf("endpoint %d = %s %s p95 %v count %d, want %s %s p95 %v count %d",
+				i, d.Endpoint, d.Status, d.P95Delta, d.CountDelta, e.endpoint, e.status, e.p95Delta, e.countDelta)
+		}
+	}
+}
+
+func TestBuildReleaseComparisonExceptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseCount uint64
+		target    uint64
+		traffic   [2]uint64 // requests served by the base and target versions
+		expected  string
+	}{
+		{name: "added", baseCount: 0, target: 5, traffic: [2]uint64{100, 100}, expected: "new"},
+		{name: "removed", baseCount: 5, target: 0, traffic: [2]uint64{100, 100}, expected: "gone"},
+		{name: "regressed", baseCount: 5, target: 10, traffic: [2]uint64{100, 100}, expected: "increased"},
+		{name: "unchanged", baseCount: 5, target: 6, traffic: [2]uint64{100, 100}, expected: ""},
+		{name: "more traffic", baseCount: 5, target: 50, traffic: [2]uint64{100, 1000}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpointStats := []models.VersionEndpointStats{
+				{AppVersion: "1.0", Endpoint: "GET /", Count: tt.traffic[0]},
+				{AppVersion: "1.1", Endpoint: "GET /", Count: tt.traffic[1]},
+			}
+			counts := []models.VersionExceptionCounts{{ExceptionHash: "abc", BaseCount: tt.baseCount, TargetCount: tt.target}}
+			comparison := BuildReleaseComparison("1.0", "1.1", endpointStats, counts)
+
+			got := ""
+			switch {
+			case len(comparison.NewExceptions) == 1:
+				got = "new"
+			case len(comparison.GoneExceptions) == 1:
+				got = "gone"
+			case len(comparison.IncreasedExceptions) == 1:
+				got = "increased"
+			}
+			if got != tt.expected {
+				t.Errorf("exception classified as %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import "backend/app/models"
+
+// BuildServiceMap derives the nodes of the service graph from its edges.
+// A name is a service node as soon as it calls something or is called as a service.
+func BuildServiceMap(edges []models.ServiceEdge) *models.ServiceMap {
+	serviceMap := &models.ServiceMap{
+		Nodes: []models.ServiceNode{},
+		Edges: edges,
+	}
+	if serviceMap.Edges == nil {
+		serviceMap.Edges = []models.ServiceEdge{}
+	}
+
+	nodeTypes := make(map[string]string)
+	var order []string
+	addNode := func(name, nodeType string) {
+		existing, ok := nodeTypes[name]
+		if !ok {
+			order = append(order, name)
+		}
+		if !ok || existing == models.ServiceNodeTypeExternal {
+			nodeTypes[name] = nodeType
+		}
+	}
+
+	for _, e := range edges {
+		addNode(e.Source, models.ServiceNodeTypeService)
+		targetType := models.ServiceNodeTypeService
+		if e.TargetType == models.ServiceNodeTypeExternal {
+			targetType = models.ServiceNodeTypeExternal
+		}
+		addNode(e.Target, targetType)
+	}
+
+	for _, name := range order {
+		serviceMap.Nodes = append(serviceMap.Nodes, models.ServiceNode{Name: name, Type: nodeTypes[name]})
+	}
+	return serviceMap
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"backend/app/models"
+	"strconv"
+)
+
+// ServiceMapScopes groups project ids by the organization that owns them, service edges are only aggregated between
+// projects of the same scope. A project without an organization is a scope of its own.
+func ServiceMapScopes(projects []*models.Project) map[string]string {
+	scopes := make(map[string]string, len(projects))
+	for _, p := range projects {
+		if p.OrganizationId != nil {
+			scopes[p.Id.String()] = "organization:" + strconv.Itoa(*p.OrganizationId)
+		} else {
+			scopes[p.Id.String()] = "project:" + p.Id.String()
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"backend/app/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestServiceMapScopes(t *testing.T) {
+	firstOrg, secondOrg := 1, 2
+	web := &models.Project{Id: uuid.New(), OrganizationId: &firstOrg}
+	api := &models.Project{Id: uuid.New(), OrganizationId: &firstOrg}
+	otherApi := &models.Project{Id: uuid.New(), OrganizationId: &secondOrg}
+	standalone := &models.Project{Id: uuid.New()}
+	otherStandalone := &models.Project{Id: uuid.New()}
+
+	scopes := ServiceMapScopes([]*models.Project{web, api, otherApi, standalone, otherStandalone})
+
+	tests := []struct {
+		name      string
+		a, b      *models.Project
+		sameScope bool
+	}{
+		{"projects of one organization", web, api, true},
+		{"projects of two organizations", api, otherApi, false},
+		{"organization and standalone project", web, standalone, false},
+		{"two standalone projects", standalone, otherStandalone, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := scopes[tt.a.Id.String()], scopes[tt.b.Id.String()]
+			if a == "" || b == "" {
+				t.Fatalf("missing scope: %q, %q", a, b)
+			}
+			if (a == b) != tt.sameScope {
+				t.Errorf("scopes %q and %q, want same scope %v", a, b, tt.sameScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"backend/app/models"
+	"time"
+)
+
+type SloBurnRateWindow struct {
+	Label    string
+	Duration time.Duration
+}
+
+// SloBurnRateWindows are the lookback windows used for multi-window burn rate tracking
+var SloBurnRateWindows = []SloBurnRateWindow{
+	{Label: "1h", Duration: time.Hour},
+	{Label: "6h", Duration: 6 * time.Hour},
+	{Label: "24h", Duration: 24 * time.Hour},
+	{Label: "72h", Duration: 72 * time.Hour},
+}
+
+// SloBurnRateThreshold returns the burn rate at which a lookback consumes budgetFraction of the error budget of the
+// SLO window, e.g. 2% of a 30 day budget in 1h is a burn rate of 14.4 and 2% of a 7 day budget in 1h is 3.36
+func SloBurnRateThreshold(window, lookback time.Duration, budgetFraction float64) float64 {
+	return budgetFraction * window.Hours() / lookback.Hours()
+}
+
+// SliPercent returns the percentage of good events, 100 when there were no events
+func SliPercent(counts models.SloCounts) float64 {
+	if counts.Total == 0 {
+		return 100
+	}
+	return float64(counts.Good) / float64(counts.Total) * 100
+}
+
+// ComputeErrorBudget returns how much of the error budget implied by target (a percentage) has been used
+func ComputeErrorBudget(target float64, counts models.SloCounts) models.SloErrorBudget {
+	bad := counts.Total - counts.Good
+	allowed := (1 - target/100) * float64(counts.Total)
+
+	budget := models.SloErrorBudget{
+		Allowed:   allowed,
+		Consumed:  bad,
+		Remaining: 1,
+	}
+	if allowed > 0 {
+		budget.Remaining = 1 - float64(bad)/allowed
+	} else if bad > 0 {
+		budget.Remaining = 0
+	}
+	return budget
+}
+
+// ComputeBurnRate returns how fast the error budget is being consumed relative to the SLO window.
+// A rate of 1 exhausts the budget exactly at the end of the window.
+func ComputeBurnRate(target float64, counts models.SloCounts) float64 {
+	allowedRatio := 1 - target/100
+	if counts.Total == 0 || allowedRatio <= 0 {
+		return 0
+	}
+	badRatio := float64(counts.Total-counts.Good) / float64(counts.Total)
+	return badRatio / allowedRatio
+}
+
+// BuildSloStatus combines the counts over the SLO window and the burn rate windows (in SloBurnRateWindows order)
+func BuildSloStatus(slo *models.Slo, windowCounts models.SloCounts, burnCounts []models.SloCounts) *models.SloStatus {
+	status := &models.SloStatus{
+		Slo:         slo,
+		Sli:         SliPercent(windowCounts),
+		Good:        windowCounts.Good,
+		Total:       windowCounts.Total,
+		ErrorBudget: ComputeErrorBudget(slo.Target, windowCounts),
+		BurnRates:   make([]models.SloBurnRate, 0, len(burnCounts)),
+	}
+
+	rates := make(map[string]float64, len(burnCounts))
+	for i, counts := range burnCounts {
+		if i >= len(SloBurnRateWindows) {
+			break
+		}
+		label := SloBurnRateWindows[i].Label
+		rate := ComputeBurnRate(slo.Target, counts)
+		rates[label] = rate
+		status.BurnRates = append(status.BurnRates, models.SloBurnRate{
+			Window: label,
+			Rate:   rate,
+			Good:   counts.Good,
+			Total:  counts.Total,
+		})
+	}
+
+	// Fast burn (2% of the budget in 1h and 5% in 6h) is critical, slow burn (10% in 24h and in 3 days) is a warning
+	window := slo.Window()
+	fastBurn := rates["1h"] >= SloBurnRateThreshold(window, time.Hour, 0.02) && rates["6h"] >= SloBurnRateThreshold(window, 6*time.Hour, 0.05)
+	slowBurn := rates["24h"] >= SloBurnRateThreshold(window, 24*time.Hour, 0.1) && rates["72h"] >= SloBurnRateThreshold(window, 72*time.Hour, 0.1)
+	switch {
+	case status.ErrorBudget.Remaining <= 0 || fastBurn:
+		status.Status = models.HealthStatusCritical
+	case status.ErrorBudget.Remaining < 0.25 || slowBurn:
+		status.Status = models.HealthStatusWarning
+	default:
+		status.Status = models.HealthStatusHealthy
+	}
+
+	return status
+}
+
+// BuildSloHistory fills in the SLI of every bucket and the budget remaining accumulated from the first bucket
+func BuildSloHistory(slo *models.Slo, points []models.SloHistoryPoint) []models.SloHistoryPoint {
+	var cumulative models.SloCounts
+	for i := range points {
+		bucket := models.SloCounts{Good: points[i].Good, Total: points[i].Total}
+		cumulative.Good += bucket.Good
+		cumulative.Total += bucket.Total
+
+		points[i].Sli = SliPercent(bucket)
+		points[i].BudgetRemaining = ComputeErrorBudget(slo.Target, cumulative).Remaining
+	}
+	return points
+}
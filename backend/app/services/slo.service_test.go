@@ -0,0 +1,111 @@
+package services
+
+import (
+	"backend/app/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeErrorBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    float64
+		counts    models.SloCounts
+		remaining float64
+	}{
+		{name: "no traffic", target: 99.9, counts: models.SloCounts{}, remaining: 1},
+		{name: "no errors", target: 99.9, counts: models.SloCounts{Good: 10000, Total: 10000}, remaining: 1},
+		{name: "half spent", target: 99, counts: models.SloCounts{Good: 9950, Total: 10000}, remaining: 0.5},
+		{name: "overspent", target: 99, counts: models.SloCounts{Good: 9800, Total: 10000}, remaining: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := ComputeErrorBudget(tt.target, tt.counts)
+			if math.Abs(budget.Remaining-tt.remaining) > 1e-9 {
+				t.Errorf("ComputeErrorBudget() remaining = %v, want %v", budget.Remaining, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestComputeBurnRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   float64
+		counts   models.SloCounts
+		expected float64
+	}{
+		{name: "no traffic", target: 99.9, counts: models.SloCounts{}, expected: 0},
+		{name: "burning at budget", target: 99, counts: models.SloCounts{Good: 99, Total: 100}, expected: 1},
+		{name: "burning 10x", target: 99, counts: models.SloCounts{Good: 90, Total: 100}, expected: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := ComputeBurnRate(tt.target, tt.counts)
+			if math.Abs(rate-tt.expected) > 1e-9 {
+				t.Errorf("ComputeBurnRate() = %v, want %v", rate, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSloBurnRateThreshold(t *testing.T) {
+	tests := []struct {
+		windowDays int
+		expected   []float64 // 1h at 2%, 6h at 5%, 24h at 10%, 72h at 10%
+	}{
+		{windowDays: 7, expected: []float64{3.36, 1.4, 0.7, 0.7 / 3}},
+		{windowDays: 28, expected: []float64{13.44, 5.6, 2.8, 2.8 / 3}},
+		{windowDays: 30, expected: []float64{14.4, 6, 3, 1}},
+	}
+
+	for _, tt := range tests {
+		slo := &models.Slo{WindowDays: tt.windowDays}
+		got := []float64{
+			SloBurnRateThreshold(slo.Window(), time.Hour, 0.02),
+			SloBurnRateThreshold(slo.Window(), 6*time.Hour, 0.05),
+			SloBurnRateThreshold(slo.Window(), 24*time.Hour, 0.1),
+			SloBurnRateThreshold(slo.Window(), 72*time.Hour, 0.1),
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.expected[i]) > 1e-9 {
+				t.Errorf("%d day window threshold %d = %v, want %v", tt.windowDays, i, got[i], tt.expected[i])
+			}
+		}
+	}
+}
+
+func TestBuildSloStatusScalesBurnRatesToTheWindow(t *testing.T) {
+	// 99% target, 5% errors in 1h and 6h is a burn rate of 5: 3.5% of a 7 day budget per hour but 0.7% of a 30 day one
+	fastBurn := []models.SloCounts{{Good: 95, Total: 100}, {Good: 95, Total: 100}, {Good: 100, Total: 100}, {Good: 100, Total: 100}}
+	// a burn rate of 1 over 24h and 72h consumes 14% of a 7 day budget in a day
+	slowBurn := []models.SloCounts{{Good: 100, Total: 100}, {Good: 100, Total: 100}, {Good: 99, Total: 100}, {Good: 99, Total: 100}}
+	window := models.SloCounts{Good: 100000, Total: 100000}
+
+	tests := []struct {
+		name       string
+		windowDays int
+		burnCounts []models.SloCounts
+		expected   string
+	}{
+		{"fast burn of a 7 day window", 7, fastBurn, models.HealthStatusCritical},
+		{"fast burn of a 28 day window", 28, fastBurn, models.HealthStatusHealthy},
+		{"fast burn of a 30 day window", 30, fastBurn, models.HealthStatusHealthy},
+		{"slow burn of a 7 day window", 7, slowBurn, models.HealthStatusWarning},
+		{"slow burn of a 28 day window", 28, slowBurn, models.HealthStatusHealthy},
+		{"slow burn of a 30 day window", 30, slowBurn, models.HealthStatusHealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := &models.Slo{Target: 99, WindowDays: tt.windowDays}
+			status := BuildSloStatus(slo, window, tt.burnCounts)
+			if status.Status != tt.expected {
+				t.Errorf("BuildSloStatus() status = %v, want %v", status.Status, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"archive/zip"
+	"backend/app/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	// maxBundleEntrySize caps each file extracted from an artifact bundle, the same limit as a single source map upload
+	maxBundleEntrySize = 50 << 20
+	// maxBundleSize caps the uncompressed size of all the files read from an artifact bundle
+	maxBundleSize    = 200 << 20
+	maxBundleEntries = 10000
+)
+
+var errBundleEntryTooLarge = errors.New("exceeds 50MB limit")
+
+var minifiedFileExtensions = []string{".js", ".mjs", ".cjs"}
+
+// BundledSourceMap is a source map read from an artifact bundle, its content is extracted again by ReadData
+type BundledSourceMap struct {
+	FileName string
+	Summary  models.SourceMapSummary
+	entry    *zip.File
+}
+
+// ReadData extracts the source map from its artifact bundle
+func (b BundledSourceMap) ReadData() ([]byte, error) {
+	return readBundleEntry(b.entry, maxBundleEntrySize)
+}
+
+// ReadArtifactBundle reads the source maps of a zip artifact bundle holding the minified files and their maps.
+// Maps without a debug ID take the one injected into the minified file referencing them through its sourceMappingURL
+// comment, or named after them when the comment is missing. Every map is validated, one that can't be parsed fails the bundle.
+// Maps are inspected one at a time and not kept in memory, the number of files and their total size are capped.
+func ReadArtifactBundle(data []byte) ([]BundledSourceMap, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact bundle: %w", err)
+	}
+
+	var bundled []BundledSourceMap
+	minifiedByMap := map[string]string{}
+	debugIdByMinified := map[string]string{}
+	entries := 0
+	var remaining int64 = maxBundleSize
+	for _, entry := range reader.File {
+		name := path.Clean(strings.TrimPrefix(entry.Name, "/"))
+		if entry.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		entries++
+		if entries > maxBundleEntries {
+			return nil, fmt.Errorf("artifact bundle exceeds %d files limit", maxBundleEntries)
+		}
+		// names become part of the storage key
+		if name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid artifact bundle entry %s", entry.Name)
+		}
+		isMap := strings.HasSuffix(name, ".map")
+		if !isMap && !isMinifiedFile(name) {
+			continue
+		}
+
+		content, err := readBundleEntry(entry, min(maxBundleEntrySize, remaining))
+		if errors.Is(err, errBundleEntryTooLarge) && remaining < maxBundleEntrySize {
+			return nil, fmt.Errorf("artifact bundle exceeds 200MB uncompressed limit")
+		}
+		if err != nil {
+			return nil, err
+		}
+		remaining -= int64(len(content))
+
+		if isMap {
+			summary, err := InspectSourceMap(name, content)
+			if err != nil {
+				return nil, err
+			}
+			bundled = append(bundled, BundledSourceMap{FileName: name, Summary: summary, entry: entry})
+			continue
+		}
+		mapName := name + ".map"
+		if url := lastCommentValue(content, "sourceMappingURL"); url != "" && !strings.HasPrefix(url, "data:") {
+			mapName = resolveSourceMappingURL(name, url)
+		}
+		minifiedByMap[mapName] = name
+		debugIdByMinified[name] = NormalizeDebugId(lastCommentValue(content, "debugId"))
+	}
+
+	if len(bundled) == 0 {
+		return nil, fmt.Errorf("invalid artifact bundle: no source maps")
+	}
+
+	for i := range bundled {
+		summary := &bundled[i].Summary
+		if minified, ok := minifiedByMap[bundled[i].FileName]; ok {
+			if summary.DebugId == "" {
+				summary.DebugId = debugIdByMinified[minified]
+			}
+			summary.File = minified
+		}
+	}
+	return bundled, nil
+}
+
+func isMinifiedFile(name string) bool {
+	for _, extension := range minifiedFileExtensions {
+		if strings.HasSuffix(name, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBundleEntry extracts a file of at most limit bytes
+func readBundleEntry(entry *zip.File, limit int64) ([]byte, error) {
+	if entry.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("file %s in artifact bundle %w", entry.Name, errBundleEntryTooLarge)
+	}
+	f, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact bundle entry %s: %w", entry.Name, err)
+	}
+	defer f.Close()
+
+	// the declared size can't be trusted, reading stops past the limit
+	content, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact bundle entry %s: %w", entry.Name, err)
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("file %s in artifact bundle %w", entry.Name, errBundleEntryTooLarge)
+	}
+	return content, nil
+}
+
+// lastCommentValue returns the value of the last "//# name=value" comment of a minified file
+func lastCommentValue(content []byte, name string) string {
+	marker := []byte("//# " + name + "=")
+	idx := bytes.LastIndex(content, marker)
+	if idx < 0 {
+		return ""
+	}
+	value := content[idx+len(marker):]
+	if end := bytes.IndexAny(value, "\r\n"); end >= 0 {
+		value = value[:end]
+	}
+	return strings.TrimSpace(string(value))
+}
+
+// resolveSourceMappingURL returns the bundle path of the map a minified file references, absolute URLs are looked up
+// next to the minified file
+func resolveSourceMappingURL(minified, url string) string {
+	if idx := strings.IndexAny(url, "?#"); idx != -1 {
+		url = url[:idx]
+	}
+	if strings.Contains(url, "://") {
+		url = path.Base(url)
+	}
+	if strings.HasPrefix(url, "/") {
+		return path.Clean(strings.TrimPrefix(url, "/"))
+	}
+	return path.Join(path.Dir(minified), url)
+}
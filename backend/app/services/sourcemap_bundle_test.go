@@ -0,0 +1,132 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func buildBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadArtifactBundle(t *testing.T) {
+	const sourceMap = `{"version":3,"sources":["src/app.ts"],"sourcesContent":["a"],"names":[],"mappings":"AAAA"}`
+
+	tests := []struct {
+		name          string
+		files         map[string]string
+		expectError   bool
+		expectFile    map[string]string
+		expectDebugId map[string]string
+	}{
+		{
+			name: "debug id from minified file",
+			files: map[string]string{
+				"assets/index-3f2a.js":     "console.log(1)\n//# debugId=5B2F6C1E-7D8A-4C3B-9E1F-0A2B3C4D5E6F\n//# sourceMappingURL=index-3f2a.js.map",
+				"assets/index-3f2a.js.map": sourceMap,
+			},
+			expectFile:    map[string]string{"assets/index-3f2a.js.map": "assets/index-3f2a.js"},
+			expectDebugId: map[string]string{"assets/index-3f2a.js.map": "5b2f6c1e-7d8a-4c3b-9e1f-0a2b3c4d5e6f"},
+		},
+		{
+			name: "debug id in map wins and maps in other directories stay apart",
+			files: map[string]string{
+				"admin/main.js":     "x\n//# debugId=11111111-1111-1111-1111-111111111111\n//# sourceMappingURL=/maps/admin.js.map",
+				"maps/admin.js.map": sourceMap,
+				"app/main.js.map":   `{"version":3,"debugId":"22222222-2222-2222-2222-222222222222","sources":["src/app.ts"],"names":[],"mappings":"AAAA"}`,
+			},
+			expectFile: map[string]string{"maps/admin.js.map": "admin/main.js", "app/main.js.map": "main.js"},
+			expectDebugId: map[string]string{
+				"maps/admin.js.map": "11111111-1111-1111-1111-111111111111",
+				"app/main.js.map":   "22222222-2222-2222-2222-222222222222",
+			},
+		},
+		{name: "invalid map", files: map[string]string{"main.js.map": "{"}, expectError: true},
+		{name: "no maps", files: map[string]string{"main.js": "x"}, expectError: true},
+		{name: "path outside the bundle", files: map[string]string{"../main.js.map": sourceMap}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundled, err := ReadArtifactBundle(buildBundle(t, tt.files))
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("ReadArtifactBundle() expected an error, got %+v", bundled)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadArtifactBundle() unexpected error: %v", err)
+			}
+			if len(bundled) != len(tt.expectFile) {
+				t.Fatalf("ReadArtifactBundle() returned %d maps, want %d", len(bundled), len(tt.expectFile))
+			}
+			for _, b := range bundled {
+				if b.Summary.File != tt.expectFile[b.FileName] {
+					t.Errorf("%s File = %q, want %q", b.FileName, b.Summary.File, tt.expectFile[b.FileName])
+				}
+				if b.Summary.DebugId != tt.expectDebugId[b.FileName] {
+					t.Errorf("%s DebugId = %q, want %q", b.FileName, b.Summary.DebugId, tt.expectDebugId[b.FileName])
+				}
+			}
+		})
+	}
+}
+
+func TestReadArtifactBundleLimits(t *testing.T) {
+	const sourceMap = `{"version":3,"sources":["src/app.ts"],"names":[],"mappings":"AAAA"}`
+
+	manyFiles := map[string]string{"main.js.map": sourceMap}
+	for i := 0; i < maxBundleEntries; i++ {
+		manyFiles[fmt.Sprintf("assets/chunk-%d.js", i)] = "x"
+	}
+
+	tests := []struct {
+		name        string
+		files       map[string]string
+		expectError string
+	}{
+		{name: "too many files", files: manyFiles, expectError: "files limit"},
+		// zeros compress well, the bundle itself stays small
+		{name: "oversized file", files: map[string]string{"main.js.map": strings.Repeat("0", maxBundleEntrySize+1)}, expectError: "50MB limit"},
+		{
+			name: "oversized bundle",
+			files: map[string]string{
+				"main.js.map": sourceMap,
+				"a.js":        strings.Repeat("0", 45<<20),
+				"b.js":        strings.Repeat("0", 45<<20),
+				"c.js":        strings.Repeat("0", 45<<20),
+				"d.js":        strings.Repeat("0", 45<<20),
+				"e.js":        strings.Repeat("0", 45<<20),
+			},
+			expectError: "200MB uncompressed limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundled, err := ReadArtifactBundle(buildBundle(t, tt.files))
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Fatalf("ReadArtifactBundle() = %d maps, %v, want an error containing %q", len(bundled), err, tt.expectError)
+			}
+		})
+	}
+}
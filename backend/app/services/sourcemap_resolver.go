@@ -4,6 +4,7 @@ import (
 	"backend/app/cache"
 	"backend/app/models"
 	"backend/app/storage"
+	"bytes"
 	"context"
 	"fmt"
 	"path/filepath"
@@ -16,7 +17,12 @@ import (
 	traceway "go.tracewayapp.com"
 )
 
-var stackFrameRe = regexp.MustCompile(`^(\s{4})(.+):(\d+):(\d+)$`)
+// stackFrameRe is the frame format of the Traceway SDK, the function is printed on the line above as "name()"
+var stackFrameRe = regexp.MustCompile(`^(\s{4})(?P<file>.+):(?P<line>\d+):(?P<column>\d+)$`)
+
+// browserFrameFormats are the frame formats the resolver symbolicates, V8 is matched before the SDK format
+// because both indent frames with four spaces
+var browserFrameFormats = []*regexp.Regexp{v8FrameRe, stackFrameRe, geckoFrameRe, jscLocationRe}
 var jsFuncDeclRe = regexp.MustCompile(
 	`(?:(?:export\s+(?:default\s+)?)?function\s+(\w+)` +
 		`|(?:const|let|var)\s+(\w+)\s*=` +
@@ -28,11 +34,34 @@ var jsControlFlowKeywords = map[string]bool{
 	"catch": true, "return": true, "throw": true, "else": true,
 }
 
-func ResolveStackTrace(ctx context.Context, projectId uuid.UUID, stackTrace string, sourceMaps []*models.SourceMap) string {
+// consumerMappingSize is the memory a parsed mapping takes, six int32 fields
+const consumerMappingSize = 24
+
+// StackTraceResolver symbolicates stack traces with source maps. Each source map is loaded at most once per resolver,
+// so the frames of one request don't parse the same map again when it is too large for the cache.
+type StackTraceResolver struct {
+	// consumers is nil for the source maps that couldn't be loaded
+	consumers map[string]*sourcemap.Consumer
+}
+
+func NewStackTraceResolver() *StackTraceResolver {
+	return &StackTraceResolver{consumers: map[string]*sourcemap.Consumer{}}
+}
+
+// Resolve symbolicates the browser frames of a stack trace. Frames of scripts with a debug ID use the source map
+// built with them, the others the source map named after their file.
+func (r *StackTraceResolver) Resolve(ctx context.Context, stackTrace string, sourceMaps []*models.SourceMap, debugIds map[string]string) string {
 	if len(sourceMaps) == 0 {
 		return stackTrace
 	}
 
+	smByDebugId := make(map[string]*models.SourceMap)
+	for _, sm := range sourceMaps {
+		if sm.DebugId != "" {
+			smByDebugId[sm.DebugId] = sm
+		}
+	}
+
 	// Build lookup: basename of source map's file_name (without .map) -> source map
 	// Also keep a map of file_name -> storage_key for direct lookup
 	smByBasename := make(map[string]*models.SourceMap)
@@ -54,31 +83,27 @@ func ResolveStackTrace(ctx context.Context, projectId uuid.UUID, stackTrace stri
 			continue
 		}
 
-		matches := stackFrameRe.FindStringSubmatch(line)
-		if matches == nil {
+		format, matches := matchBrowserFrame(line)
+		if format == nil {
 			resolved = append(resolved, line)
 			continue
 		}
 
-		indent := matches[1]
-		fileName := matches[2]
-		lineNum, _ := strconv.Atoi(matches[3])
-		colNum, _ := strconv.Atoi(matches[4])
+		fileName := submatch(line, matches, format, "file")
+		lineNum, _ := strconv.Atoi(submatch(line, matches, format, "line"))
+		colNum, _ := strconv.Atoi(submatch(line, matches, format, "column"))
 
-		sm := findSourceMap(fileName, smByBasename)
+		sm := smByDebugId[NormalizeDebugId(debugIds[fileName])]
 		if sm == nil {
-			resolved = append(resolved, line)
-			continue
+			sm = findSourceMap(fileName, smByBasename)
 		}
-
-		data, err := getSourceMapData(ctx, sm.StorageKey)
-		if err != nil {
+		if sm == nil {
 			resolved = append(resolved, line)
 			continue
 		}
 
-		consumer, err := sourcemap.Parse("", data)
-		if err != nil {
+		consumer := r.consumer(ctx, sm.StorageKey)
+		if consumer == nil {
 			resolved = append(resolved, line)
 			continue
 		}
@@ -95,10 +120,10 @@ func ResolveStackTrace(ctx context.Context, projectId uuid.UUID, stackTrace stri
 			}
 		}
 
-		resolved = append(resolved, fmt.Sprintf("%s%s:%d:%d", indent, origFile, origLine, origCol))
+		resolved = append(resolved, rewriteBrowserFrame(line, matches, format, origName, fmt.Sprintf("%s:%d:%d", origFile, origLine, origCol)))
 		framesResolved++
 
-		if origName != "" && len(resolved) >= 2 {
+		if format == stackFrameRe && origName != "" && len(resolved) >= 2 {
 			prev := resolved[len(resolved)-2]
 			if strings.HasSuffix(strings.TrimSpace(prev), "()") {
 				trimmed := strings.TrimSpace(prev)
@@ -111,6 +136,117 @@ func ResolveStackTrace(ctx context.Context, projectId uuid.UUID, stackTrace stri
 	return strings.Join(resolved, "\n")
 }
 
+// ResolveExceptions symbolicates exceptions with the source maps of their app version, loaded once per version, and the
+// source maps matching the debug IDs they were reported with, loaded once for all exceptions.
+// Resolving starts from the trace as received so exceptions can be resolved again after more source maps are uploaded,
+// exceptions whose trace changed get a new hash and frames. It returns copies of the exceptions that changed,
+// the given exceptions are left as stored.
+func ResolveExceptions(ctx context.Context, projectId uuid.UUID, exceptions []models.ExceptionStackTrace, loadSourceMaps func(version string) []*models.SourceMap, loadDebugIdSourceMaps func(debugIds []string) []*models.SourceMap) []models.ExceptionStackTrace {
+	resolver := NewStackTraceResolver()
+	var debugIdSourceMaps []*models.SourceMap
+	debugIdMaps := make([]map[string]string, len(exceptions))
+	for i, est := range exceptions {
+		debugIdMaps[i] = est.DebugIds
+	}
+	if debugIds := CollectDebugIds(debugIdMaps); len(debugIds) > 0 && loadDebugIdSourceMaps != nil {
+		debugIdSourceMaps = loadDebugIdSourceMaps(debugIds)
+	}
+
+	sourceMapsByVersion := map[string][]*models.SourceMap{}
+	var changed []models.ExceptionStackTrace
+	for i := range exceptions {
+		est := &exceptions[i]
+		if est.IsMessage {
+			continue
+		}
+
+		sourceMaps, loaded := sourceMapsByVersion[est.AppVersion]
+		if !loaded {
+			sourceMaps = loadSourceMaps(est.AppVersion)
+			sourceMapsByVersion[est.AppVersion] = sourceMaps
+		}
+		if len(est.DebugIds) > 0 && len(debugIdSourceMaps) > 0 {
+			sourceMaps = append(append([]*models.SourceMap{}, sourceMaps...), debugIdSourceMaps...)
+		}
+		if len(sourceMaps) == 0 {
+			continue
+		}
+
+		raw := est.RawStackTrace
+		if raw == "" {
+			raw = est.StackTrace
+		}
+		resolved := resolver.Resolve(ctx, raw, sourceMaps, est.DebugIds)
+		if resolved == est.StackTrace {
+			continue
+		}
+
+		rewritten := *est
+		rewritten.RawStackTrace = raw
+		rewritten.StackTrace = resolved
+		rewritten.ExceptionHash = ComputeExceptionHash(resolved, false)
+		rewritten.Frames = ParseStackTrace(resolved).Frames
+		changed = append(changed, rewritten)
+	}
+	return changed
+}
+
+// CollectDebugIds returns the distinct normalized debug IDs of the script URL to debug ID maps exceptions are reported with
+func CollectDebugIds(debugIdMaps []map[string]string) []string {
+	seen := map[string]bool{}
+	var debugIds []string
+	for _, debugIdMap := range debugIdMaps {
+		for _, debugId := range debugIdMap {
+			debugId = NormalizeDebugId(debugId)
+			if debugId != "" && !seen[debugId] {
+				seen[debugId] = true
+				debugIds = append(debugIds, debugId)
+			}
+		}
+	}
+	return debugIds
+}
+
+// HasBrowserFrames reports whether a stack trace has a frame the resolver can symbolicate, only JavaScript frames
+// carry a column
+func HasBrowserFrames(stackTrace string) bool {
+	for _, line := range strings.Split(stackTrace, "\n") {
+		if format, _ := matchBrowserFrame(line); format != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func matchBrowserFrame(line string) (*regexp.Regexp, []int) {
+	for _, format := range browserFrameFormats {
+		if matches := format.FindStringSubmatchIndex(line); matches != nil {
+			return format, matches
+		}
+	}
+	return nil, nil
+}
+
+func submatch(line string, matches []int, format *regexp.Regexp, name string) string {
+	i := format.SubexpIndex(name)
+	if i < 0 || matches[2*i] < 0 {
+		return ""
+	}
+	return line[matches[2*i]:matches[2*i+1]]
+}
+
+// rewriteBrowserFrame replaces the location of a frame and, for formats that print it on the same line, the function name
+func rewriteBrowserFrame(line string, matches []int, format *regexp.Regexp, function, location string) string {
+	file := format.SubexpIndex("file")
+	column := format.SubexpIndex("column")
+	rewritten := line[:matches[2*file]] + location + line[matches[2*column+1]:]
+
+	if i := format.SubexpIndex("function"); i >= 0 && function != "" && matches[2*i] >= 0 {
+		rewritten = rewritten[:matches[2*i]] + function + rewritten[matches[2*i+1]:]
+	}
+	return rewritten
+}
+
 func findSourceMap(stackFile string, smByBasename map[string]*models.SourceMap) *models.SourceMap {
 	// Try file.map directly
 	mapName := stackFile + ".map"
@@ -137,19 +273,43 @@ func findSourceMap(stackFile string, smByBasename map[string]*models.SourceMap)
 	return nil
 }
 
-func getSourceMapData(ctx context.Context, storageKey string) ([]byte, error) {
-	if data, ok := cache.SourceMapCache.Get(storageKey); ok {
-		return data, nil
+func (r *StackTraceResolver) consumer(ctx context.Context, storageKey string) *sourcemap.Consumer {
+	if consumer, loaded := r.consumers[storageKey]; loaded {
+		return consumer
+	}
+	consumer, err := getSourceMapConsumer(ctx, storageKey)
+	if err != nil {
+		traceway.CaptureException(err)
+	}
+	r.consumers[storageKey] = consumer
+	return consumer
+}
+
+func getSourceMapConsumer(ctx context.Context, storageKey string) (*sourcemap.Consumer, error) {
+	if consumer, ok := cache.SourceMapCache.Get(storageKey); ok {
+		return consumer, nil
 	}
 
 	data, err := storage.Store.Read(ctx, storageKey)
 	if err != nil {
-		traceway.CaptureException(fmt.Errorf("failed to read source map from storage (key=%s): %w", storageKey, err))
-		return nil, err
+		return nil, fmt.Errorf("failed to read source map from storage (key=%s): %w", storageKey, err)
 	}
 
-	cache.SourceMapCache.Put(storageKey, data)
-	return data, nil
+	consumer, err := sourcemap.Parse("", data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source map (key=%s): %w", storageKey, err)
+	}
+
+	cache.SourceMapCache.Put(storageKey, consumer, estimateConsumerSize(data))
+	return consumer, nil
+}
+
+// estimateConsumerSize approximates the memory of a parsed source map: the names and sources content are kept as read
+// and every mapping, separated by a comma or a semicolon, takes a fixed size. Separators elsewhere in the file make it
+// an overestimate.
+func estimateConsumerSize(data []byte) int64 {
+	separators := bytes.Count(data, []byte(",")) + bytes.Count(data, []byte(";"))
+	return int64(len(data)) + int64(separators)*consumerMappingSize
 }
 
 func extractFunctionName(sourceContent string, line int) string {
@@ -1,6 +1,16 @@
 package services
 
-import "testing"
+import (
+	"backend/app/cache"
+	"backend/app/models"
+	"backend/app/storage"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
 
 func TestExtractFunctionName(t *testing.T) {
 	tests := []struct {
@@ -127,3 +137,205 @@ z = x + y`,
 		})
 	}
 }
+
+func TestRewriteBrowserFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		file     string
+		expected string
+	}{
+		{
+			name:     "chrome with function",
+			line:     "    at a (https://app.example.com/assets/main.js:1:2043)",
+			file:     "https://app.example.com/assets/main.js",
+			expected: "    at handleClick (src/App.tsx:12:5)",
+		},
+		{
+			name:     "chrome without function",
+			line:     "    at https://app.example.com/assets/main.js:1:2043",
+			file:     "https://app.example.com/assets/main.js",
+			expected: "    at src/App.tsx:12:5",
+		},
+		{
+			name:     "firefox",
+			line:     "a@https://app.example.com/assets/main.js:1:2043",
+			file:     "https://app.example.com/assets/main.js",
+			expected: "handleClick@src/App.tsx:12:5",
+		},
+		{
+			name:     "safari anonymous",
+			line:     "@https://app.example.com/assets/main.js:1:2043",
+			file:     "https://app.example.com/assets/main.js",
+			expected: "handleClick@src/App.tsx:12:5",
+		},
+		{
+			name:     "safari bare location",
+			line:     "https://app.example.com/assets/main.js:1:2043",
+			file:     "https://app.example.com/assets/main.js",
+			expected: "src/App.tsx:12:5",
+		},
+		{
+			name:     "traceway sdk",
+			line:     "    assets/main.js:1:2043",
+			file:     "assets/main.js",
+			expected: "    src/App.tsx:12:5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, matches := matchBrowserFrame(tt.line)
+			if format == nil {
+				t.Fatalf("matchBrowserFrame(%q) didn't match", tt.line)
+			}
+			if file := submatch(tt.line, matches, format, "file"); file != tt.file {
+				t.Errorf("file = %q, want %q", file, tt.file)
+			}
+			got := rewriteBrowserFrame(tt.line, matches, format, "handleClick", "src/App.tsx:12:5")
+			if got != tt.expected {
+				t.Errorf("rewriteBrowserFrame() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+type countingStore struct {
+	files map[string][]byte
+	reads int
+}
+
+func (s *countingStore) Write(_ context.Context, key string, data []byte) error {
+	s.files[key] = data
+	return nil
+}
+
+func (s *countingStore) Read(_ context.Context, key string) ([]byte, error) {
+	s.reads++
+	data, ok := s.files[key]
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", key)
+	}
+	return data, nil
+}
+
+func (s *countingStore) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func TestStackTraceResolverParsesEachSourceMapOnce(t *testing.T) {
+	previousStore, previousCache := storage.Store, cache.SourceMapCache
+	defer func() { storage.Store, cache.SourceMapCache = previousStore, previousCache }()
+
+	store := &countingStore{files: map[string][]byte{
+		"sourcemaps/main.js.map": []byte(`{"version":3,"sources":["src/app.ts"],"names":[],"mappings":"AAAA,IAAI"}`),
+	}}
+	storage.Store = store
+	sourceMaps := []*models.SourceMap{{FileName: "main.js.map", StorageKey: "sourcemaps/main.js.map"}}
+	stackTrace := "Error: boom\n    at a (https://app.example.com/main.js:1:1)\n    at b (https://app.example.com/main.js:1:5)"
+
+	tests := []struct {
+		name          string
+		maxBytes      int64
+		expectedReads int
+	}{
+		// the map doesn't fit the cache, the resolver still parses it once per request
+		{"too large to cache", 1, 2},
+		{"cached", 1 << 20, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store.reads = 0
+			cache.InitSourceMapCache(10, tt.maxBytes)
+
+			for i := 0; i < 2; i++ {
+				resolved := NewStackTraceResolver().Resolve(context.Background(), stackTrace, sourceMaps, nil)
+				if !strings.Contains(resolved, "src/app.ts:1:") {
+					t.Fatalf("Resolve() = %q, want frames resolved to src/app.ts", resolved)
+				}
+			}
+			if store.reads != tt.expectedReads {
+				t.Errorf("source map read %d times, want %d", store.reads, tt.expectedReads)
+			}
+		})
+	}
+}
+
+func TestResolveExceptions(t *testing.T) {
+	previousStore, previousCache := storage.Store, cache.SourceMapCache
+	defer func() { storage.Store, cache.SourceMapCache = previousStore, previousCache }()
+
+	storage.Store = &countingStore{files: map[string][]byte{
+		"sourcemaps/main.js.map": []byte(`{"version":3,"sources":["src/app.ts"],"names":[],"mappings":"AAAA,IAAI"}`),
+	}}
+	cache.InitSourceMapCache(10, 1<<20)
+	sourceMaps := []*models.SourceMap{{FileName: "main.js.map", StorageKey: "sourcemaps/main.js.map"}}
+
+	minified := "Error: boom\n    at a (https://app.example.com/main.js:1:1)"
+	resolved := NewStackTraceResolver().Resolve(context.Background(), minified, sourceMaps, nil)
+	if resolved == minified {
+		t.Fatalf("Resolve() left the trace unchanged")
+	}
+
+	unresolved := models.ExceptionStackTrace{Id: uuid.New(), AppVersion: "1.0.0", StackTrace: minified, ExceptionHash: ComputeExceptionHash(minified, false)}
+	alreadyResolved := models.ExceptionStackTrace{Id: uuid.New(), AppVersion: "1.0.0", StackTrace: resolved, RawStackTrace: minified, ExceptionHash: ComputeExceptionHash(resolved, false)}
+	message := models.ExceptionStackTrace{Id: uuid.New(), AppVersion: "1.0.0", StackTrace: minified, IsMessage: true}
+	otherVersion := models.ExceptionStackTrace{Id: uuid.New(), AppVersion: "2.0.0", StackTrace: minified}
+	exceptions := []models.ExceptionStackTrace{unresolved, alreadyResolved, message, otherVersion}
+
+	loads := map[string]int{}
+	changed := ResolveExceptions(context.Background(), uuid.New(), exceptions, func(version string) []*models.SourceMap {
+		loads[version]++
+		if version == "1.0.0" {
+			return sourceMaps
+		}
+		return nil
+	}, nil)
+
+	if len(changed) != 1 {
+		t.Fatalf("ResolveExceptions() changed %d exceptions, want 1", len(changed))
+	}
+	got := changed[0]
+	if got.Id != unresolved.Id {
+		t.Errorf("changed exception %s, want %s", got.Id, unresolved.Id)
+	}
+	// the raw trace wasn't stored, the received trace is kept as raw
+	if got.RawStackTrace != minified || got.StackTrace != resolved {
+		t.Errorf("raw = %q, resolved = %q", got.RawStackTrace, got.StackTrace)
+	}
+	if got.ExceptionHash != ComputeExceptionHash(resolved, false) || got.ExceptionHash == unresolved.ExceptionHash {
+		t.Errorf("ExceptionHash = %q, want the hash of the resolved trace", got.ExceptionHash)
+	}
+	if len(got.Frames) == 0 {
+		t.Errorf("Frames weren't parsed from the resolved trace")
+	}
+	if exceptions[0].StackTrace != minified || exceptions[0].ExceptionHash != unresolved.ExceptionHash {
+		t.Errorf("ResolveExceptions() modified the exceptions it was given")
+	}
+	if loads["1.0.0"] != 1 || loads["2.0.0"] != 1 {
+		t.Errorf("source maps loaded %v, want once per version", loads)
+	}
+}
+
+func TestHasBrowserFrames(t *testing.T) {
+	tests := []struct {
+		name       string
+		stackTrace string
+		expected   bool
+	}{
+		{"v8", "TypeError: x is undefined\n    at render (https://app.example.com/main.js:1:120)", true},
+		{"gecko", "render@https://app.example.com/main.js:1:120", true},
+		{"go", "panic: boom\nmain.handler()\n\t/app/main.go:42 +0x1d", false},
+		{"java", "java.lang.IllegalStateException: boom\n\tat com.example.Handler.handle(Handler.java:42)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasBrowserFrames(tt.stackTrace); got != tt.expected {
+				t.Errorf("HasBrowserFrames() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"backend/app/models"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-sourcemap/sourcemap"
+	"github.com/google/uuid"
+)
+
+// sourceMapFile holds the fields of a source map that describe what it covers, index maps nest their maps in sections
+type sourceMapFile struct {
+	File           string    `json:"file"`
+	DebugId        string    `json:"debugId"`
+	LegacyDebugId  string    `json:"debug_id"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent"`
+	Sections       []struct {
+		Map *sourceMapFile `json:"map"`
+	} `json:"sections"`
+}
+
+// InspectSourceMap validates an uploaded source map and describes the minified file and the sources it covers.
+// A map that can't be parsed or maps no sources is rejected, the resolver could not use it.
+func InspectSourceMap(fileName string, data []byte) (models.SourceMapSummary, error) {
+	summary := models.SourceMapSummary{Sources: []string{}, MissingContent: []string{}}
+
+	if _, err := sourcemap.Parse("", data); err != nil {
+		return summary, fmt.Errorf("invalid source map %s: %w", fileName, err)
+	}
+
+	var file sourceMapFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return summary, fmt.Errorf("invalid source map %s: %w", fileName, err)
+	}
+
+	summary.File = file.File
+	if summary.File == "" {
+		// the resolver matches frames to the map named after their file
+		summary.File = strings.TrimSuffix(path.Base(fileName), ".map")
+	}
+
+	summary.DebugId = NormalizeDebugId(file.DebugId)
+	if summary.DebugId == "" {
+		summary.DebugId = NormalizeDebugId(file.LegacyDebugId)
+	}
+
+	collectSources(&file, &summary)
+	if len(summary.Sources) == 0 {
+		return summary, fmt.Errorf("invalid source map %s: no sources", fileName)
+	}
+	summary.SourcesContentEmbedded = len(summary.MissingContent) == 0
+	return summary, nil
+}
+
+// NormalizeDebugId returns the canonical form of a debug ID, bundlers print them as UUIDs in either case
+func NormalizeDebugId(debugId string) string {
+	debugId = strings.TrimSpace(debugId)
+	if parsed, err := uuid.Parse(debugId); err == nil {
+		return parsed.String()
+	}
+	return strings.ToLower(debugId)
+}
+
+func collectSources(file *sourceMapFile, summary *models.SourceMapSummary) {
+	for i, source := range file.Sources {
+		summary.Sources = append(summary.Sources, source)
+		if i >= len(file.SourcesContent) || file.SourcesContent[i] == nil {
+			summary.MissingContent = append(summary.MissingContent, source)
+		}
+	}
+	for _, section := range file.Sections {
+		if section.Map != nil {
+			collectSources(section.Map, summary)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInspectSourceMap(t *testing.T) {
+	tests := []struct {
+		name           string
+		fileName       string
+		data           string
+		expectError    bool
+		expectFile     string
+		expectSources  []string
+		expectEmbedded bool
+		expectMissing  []string
+	}{
+		{
+			name:           "embedded content",
+			fileName:       "main.js.map",
+			data:           `{"version":3,"file":"main.min.js","sources":["src/app.ts","src/util.ts"],"sourcesContent":["a","b"],"names":[],"mappings":"AAAA;ACAA"}`,
+			expectFile:     "main.min.js",
+			expectSources:  []string{"src/app.ts", "src/util.ts"},
+			expectEmbedded: true,
+			expectMissing:  []string{},
+		},
+		{
+			name:          "missing content falls back to the upload name",
+			fileName:      "assets/main.js.map",
+			data:          `{"version":3,"sources":["src/app.ts","src/util.ts"],"sourcesContent":["a",null],"names":[],"mappings":"AAAA;ACAA"}`,
+			expectFile:    "main.js",
+			expectSources: []string{"src/app.ts", "src/util.ts"},
+			expectMissing: []string{"src/util.ts"},
+		},
+		{
+			name:           "index map",
+			fileName:       "bundle.js.map",
+			data:           `{"version":3,"file":"bundle.js","sections":[{"offset":{"line":0,"column":0},"map":{"version":3,"sources":["a.js"],"sourcesContent":["x"],"names":[],"mappings":"AAAA"}}]}`,
+			expectFile:     "bundle.js",
+			expectSources:  []string{"a.js"},
+			expectEmbedded: true,
+			expectMissing:  []string{},
+		},
+		{name: "not json", fileName: "main.js.map", data: `<html>`, expectError: true},
+		{name: "no sources", fileName: "main.js.map", data: `{"version":3,"sources":[],"names":[],"mappings":""}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := InspectSourceMap(tt.fileName, []byte(tt.data))
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("InspectSourceMap() expected an error, got %+v", summary)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InspectSourceMap() unexpected error: %v", err)
+			}
+			if summary.File != tt.expectFile {
+				t.Errorf("File = %q, want %q", summary.File, tt.expectFile)
+			}
+			if !reflect.DeepEqual(summary.Sources, tt.expectSources) {
+				t.Errorf("Sources = %v, want %v", summary.Sources, tt.expectSources)
+			}
+			if summary.SourcesContentEmbedded != tt.expectEmbedded {
+				t.Errorf("SourcesContentEmbedded = %v, want %v", summary.SourcesContentEmbedded, tt.expectEmbedded)
+			}
+			if !reflect.DeepEqual(summary.MissingContent, tt.expectMissing) {
+				t.Errorf("MissingContent = %v, want %v", summary.MissingContent, tt.expectMissing)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"backend/app/models"
+	"sort"
+)
+
+// NestSpansByTime sets the parent of the spans of one trace that didn't record it to the tightest span running
+// around them. The report SDKs send their spans flat, without it the self time of a span would include the time
+// of the spans nested in it.
+func NestSpansByTime(spans []models.Span) {
+	// parents sort before their children, so only earlier spans are candidates and no two spans adopt each other
+	order := make([]int, len(spans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := spans[order[i]], spans[order[j]]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		return a.Duration > b.Duration
+	})
+
+	for i, idx := range order {
+		span := &spans[idx]
+		if span.ParentId != nil {
+			continue
+		}
+		end := span.StartTime.Add(span.Duration)
+
+		var parent *models.Span
+		for _, candidateIdx := range order[:i] {
+			candidate := &spans[candidateIdx]
+			if candidate.StartTime.Add(candidate.Duration).Before(end) {
+				continue
+			}
+			if parent == nil || candidate.Duration <= parent.Duration {
+				parent = candidate
+			}
+		}
+		if parent != nil {
+			parentId := parent.Id
+			span.ParentId = &parentId
+		}
+	}
+}
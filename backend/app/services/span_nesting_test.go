@@ -0,0 +1,42 @@
+package services
+
+import (
+	"backend/app/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNestSpansByTime(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	recordedParent := uuid.New()
+
+	handler := models.Span{Id: uuid.New(), Name: "handler", StartTime: start, Duration: 100 * time.Millisecond}
+	loadUser := models.Span{Id: uuid.New(), Name: "load user", StartTime: start.Add(10 * time.Millisecond), Duration: 40 * time.Millisecond}
+	query := models.Span{Id: uuid.New(), Name: "SELECT users", StartTime: start.Add(20 * time.Millisecond), Duration: 10 * time.Millisecond}
+	sameRange := models.Span{Id: uuid.New(), Name: "same range", StartTime: start.Add(20 * time.Millisecond), Duration: 10 * time.Millisecond}
+	render := models.Span{Id: uuid.New(), Name: "render", StartTime: start.Add(60 * time.Millisecond), Duration: 30 * time.Millisecond}
+	overlapping := models.Span{Id: uuid.New(), Name: "overlapping", StartTime: start.Add(90 * time.Millisecond), Duration: 30 * time.Millisecond}
+	explicit := models.Span{Id: uuid.New(), Name: "explicit", StartTime: start.Add(65 * time.Millisecond), Duration: 5 * time.Millisecond, ParentId: &recordedParent}
+
+	// recorded out of order
+	spans := []models.Span{query, render, handler, explicit, overlapping, sameRange, loadUser}
+	NestSpansByTime(spans)
+
+	expected := map[string]*uuid.UUID{
+		"handler":      nil,
+		"load user":    &handler.Id,
+		"SELECT users": &loadUser.Id,
+		"same range":   &query.Id,
+		"render":       &handler.Id,
+		"overlapping":  nil,
+		"explicit":     &recordedParent,
+	}
+	for _, s := range spans {
+		want := expected[s.Name]
+		if (s.ParentId == nil) != (want == nil) || (want != nil && *s.ParentId != *want) {
+			t.Errorf("%s parent = %v, want %v", s.Name, s.ParentId, want)
+		}
+	}
+}
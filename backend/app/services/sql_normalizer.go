@@ -0,0 +1,149 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// (?, ?, ?) becomes (?) so IN lists of different lengths group together
+	sqlPlaceholderListRegex = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	// VALUES (?), (?), (?) becomes VALUES (?) for multi-row inserts
+	sqlRepeatedTupleRegex = regexp.MustCompile(`\(\?\)(?:\s*,\s*\(\?\))+`)
+)
+
+var sqlStatementKeywords = map[string]bool{
+	"SELECT":  true,
+	"INSERT":  true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"WITH":    true,
+	"REPLACE": true,
+	"MERGE":   true,
+	"UPSERT":  true,
+	"CALL":    true,
+	"EXEC":    true,
+}
+
+// LooksLikeSQL reports whether a span name is a SQL statement, SDK database wrappers name spans after the query
+func LooksLikeSQL(name string) bool {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return false
+	}
+	return sqlStatementKeywords[strings.ToUpper(fields[0])]
+}
+
+// NormalizeSQL strips literals and comments from a statement so executions with different values group together.
+// String and numeric literals and positional parameters ($1) become ?, placeholder lists collapse to a single ?
+// and whitespace is collapsed. Quoted identifiers are kept.
+func NormalizeSQL(statement string) string {
+	var b strings.Builder
+	b.Grow(len(statement))
+
+	pendingSpace := false
+	write := func(s string) {
+		if pendingSpace && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		b.WriteString(s)
+	}
+
+	n := len(statement)
+	for i := 0; i < n; {
+		ch := statement[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			pendingSpace = true
+			i++
+
+		case ch == '-' && i+1 < n && statement[i+1] == '-':
+			for i < n && statement[i] != '\n' {
+				i++
+			}
+			pendingSpace = true
+
+		case ch == '/' && i+1 < n && statement[i+1] == '*':
+			end := strings.Index(statement[i+2:], "*/")
+			if end < 0 {
+				i = n
+			} else {
+				i += end + 4
+			}
+			pendingSpace = true
+
+		case ch == '\'':
+			i++
+			for i < n {
+				if statement[i] == '\\' && i+1 < n {
+					i += 2
+					continue
+				}
+				if statement[i] == '\'' {
+					// '' is an escaped quote inside the literal
+					if i+1 < n && statement[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			i++
+			write("?")
+
+		case ch == '"' || ch == '`':
+			end := strings.IndexByte(statement[i+1:], ch)
+			if end < 0 {
+				write(statement[i:])
+				i = n
+			} else {
+				write(statement[i : i+end+2])
+				i += end + 2
+			}
+
+		case ch == '$' && i+1 < n && isDigit(statement[i+1]):
+			i++
+			for i < n && isDigit(statement[i]) {
+				i++
+			}
+			write("?")
+
+		case isDigit(ch):
+			i++
+			for i < n && (isDigit(statement[i]) || statement[i] == '.' || statement[i] == 'x' || statement[i] == 'X' || isHexLetter(statement[i])) {
+				i++
+			}
+			write("?")
+
+		case isIdentifierChar(ch):
+			start := i
+			for i < n && isIdentifierChar(statement[i]) {
+				i++
+			}
+			write(statement[start:i])
+
+		default:
+			write(statement[i : i+1])
+			i++
+		}
+	}
+
+	normalized := sqlPlaceholderListRegex.ReplaceAllString(b.String(), "(?)")
+	normalized = sqlRepeatedTupleRegex.ReplaceAllString(normalized, "(?)")
+	return normalized
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isHexLetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+// isIdentifierChar keeps digits that are part of a name together with it, e.g. table2 or col_1
+func isIdentifierChar(ch byte) bool {
+	return ch == '_' || isDigit(ch) || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
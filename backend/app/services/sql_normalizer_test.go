@@ -0,0 +1,77 @@
+package services
+
+import "testing"
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{
+			name:      "numeric and string literals",
+			statement: "SELECT * FROM users WHERE id = 42 AND email = 'a@b.com'",
+			expected:  "SELECT * FROM users WHERE id = ? AND email = ?",
+		},
+		{
+			name:      "escaped quotes inside a string",
+			statement: "SELECT id FROM notes WHERE body = 'it''s done' AND flag = 'a\\'b'",
+			expected:  "SELECT id FROM notes WHERE body = ? AND flag = ?",
+		},
+		{
+			name:      "in lists of different lengths",
+			statement: "SELECT id FROM orders WHERE user_id IN (1, 2, 3, 4)",
+			expected:  "SELECT id FROM orders WHERE user_id IN (?)",
+		},
+		{
+			name:      "positional parameters",
+			statement: "UPDATE users SET name = $1 WHERE id = $2",
+			expected:  "UPDATE users SET name = ? WHERE id = ?",
+		},
+		{
+			name:      "multi-row insert",
+			statement: "INSERT INTO tags (post_id, name) VALUES (1, 'go'), (1, 'sql'), (2, 'db')",
+			expected:  "INSERT INTO tags (post_id, name) VALUES (?)",
+		},
+		{
+			name:      "whitespace and comments",
+			statement: "SELECT id\n\tFROM  users -- primary lookup\nWHERE id = 7 /* hint */ LIMIT 10",
+			expected:  "SELECT id FROM users WHERE id = ? LIMIT ?",
+		},
+		{
+			name:      "digits in identifiers and quoted identifiers are kept",
+			statement: `SELECT col_1, "Table2".x FROM table2 WHERE v2 = 0x1F AND price > 10.5`,
+			expected:  `SELECT col_1, "Table2".x FROM table2 WHERE v2 = ? AND price > ?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeSQL(tt.statement)
+			if got != tt.expected {
+				t.Errorf("NormalizeSQL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLooksLikeSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"SELECT id FROM users WHERE id = ?", true},
+		{"with recent as (select 1) select * from recent", true},
+		{"GET /users", false},
+		{"select", false},
+		{"process payment", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LooksLikeSQL(tt.name); got != tt.expected {
+				t.Errorf("LooksLikeSQL(%q) = %v, want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
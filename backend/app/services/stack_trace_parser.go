@@ -0,0 +1,220 @@
+package services
+
+import (
+	"backend/app/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// TypeError: x is undefined, *errors.errorString: connection refused, java.lang.IllegalStateException: closed
+	exceptionHeaderRe = regexp.MustCompile(`^([\w$.*<>\[\]]+):\s*(.*)$`)
+	// Python prints exceptions raised without a message by their type alone
+	exceptionTypeRe = regexp.MustCompile(`^[\w.]*(?:Error|Exception|Exit|Interrupt|Warning)$`)
+
+	// V8: "    at handler (/app/server.js:10:15)", "    at /app/server.js:10:15"
+	v8FrameRe = regexp.MustCompile(`^\s*at (?:async )?(?:(?P<function>.+?) \()?(?P<file>.+?):(?P<line>\d+):(?P<column>\d+)\)?$`)
+	// SpiderMonkey and JavaScriptCore: "handler@https://app.com/main.js:10:15", "@https://app.com/main.js:10:15"
+	geckoFrameRe = regexp.MustCompile(`^\s*(?P<function>.*?)@(?P<file>.+?):(?P<line>\d+):(?P<column>\d+)$`)
+	// JavaScriptCore prints frames of anonymous code as the bare location: "https://app.com/main.js:10:15"
+	jscLocationRe = regexp.MustCompile(`^\s*(?P<file>[a-z][\w+.-]*://.+?):(?P<line>\d+):(?P<column>\d+)$`)
+	// Java: "	at com.example.Service.handle(Service.java:42)", "	at java.base/java.lang.Thread.run(Thread.java:833)"
+	javaFrameRe = regexp.MustCompile(`^\s*at (?:[\w.$-]+(?:@[\w.-]+)?/)?([\w.$<>]+)\.([\w$<>]+)\(([^:)]*)(?::(\d+))?\)$`)
+	// Python: `  File "/app/views.py", line 12, in handle`
+	pythonFrameRe = regexp.MustCompile(`^\s*File "(.+)", line (\d+)(?:, in (.+))?$`)
+
+	// Go panics print the function and the location on separate lines: "main.handle(0x1)" then "	/app/main.go:12 +0x1d"
+	goFunctionRe = regexp.MustCompile(`^([\w./*()\-]+?)\(.*\)$`)
+	goLocationRe = regexp.MustCompile(`^\t(.+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	// Traceway SDKs print "handleRequest()" then "    handler.go:42" or "    main.js:10:15"
+	tracewayLocationRe = regexp.MustCompile(`^ {4}(.+?):(\d+)(?::(\d+))?$`)
+)
+
+const pythonTracebackHeader = "Traceback (most recent call last):"
+
+// ParseStackTrace reads the exception type, message and frames of a Go, JavaScript (V8, SpiderMonkey, JavaScriptCore),
+// Python or Java stack trace. Unrecognized lines are skipped, a trace without frames keeps its type and message.
+func ParseStackTrace(stackTrace string) models.ParsedStackTrace {
+	lines := strings.Split(strings.ReplaceAll(stackTrace, "\r\n", "\n"), "\n")
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == pythonTracebackHeader {
+			return parsePythonTraceback(lines)
+		}
+	}
+
+	var parsed models.ParsedStackTrace
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		// only the outermost exception is parsed, its causes follow
+		if strings.HasPrefix(line, "Caused by: ") {
+			break
+		}
+
+		if frame, ok := parseFrameLine(line); ok {
+			parsed.Frames = append(parsed.Frames, frame)
+			continue
+		}
+		if i+1 < len(lines) {
+			if frame, ok := parseTwoLineFrame(line, lines[i+1]); ok {
+				parsed.Frames = append(parsed.Frames, frame)
+				i++
+				continue
+			}
+		}
+
+		if len(parsed.Frames) == 0 && parsed.Type == "" && parsed.Message == "" {
+			parsed.Type, parsed.Message = parseExceptionHeader(line)
+		}
+	}
+
+	return parsed
+}
+
+func parseFrameLine(line string) (models.ExceptionFrame, bool) {
+	if m := v8FrameRe.FindStringSubmatch(line); m != nil {
+		return models.ExceptionFrame{Function: m[1], File: m[2], Line: atoi(m[3]), Column: atoi(m[4])}, true
+	}
+	if m := javaFrameRe.FindStringSubmatch(line); m != nil {
+		return models.ExceptionFrame{Function: m[2], File: m[3], Line: atoi(m[4]), Module: m[1]}, true
+	}
+	if m := geckoFrameRe.FindStringSubmatch(line); m != nil {
+		return models.ExceptionFrame{Function: m[1], File: m[2], Line: atoi(m[3]), Column: atoi(m[4])}, true
+	}
+	if m := jscLocationRe.FindStringSubmatch(line); m != nil {
+		return models.ExceptionFrame{File: m[1], Line: atoi(m[2]), Column: atoi(m[3])}, true
+	}
+	return models.ExceptionFrame{}, false
+}
+
+func parseTwoLineFrame(functionLine, locationLine string) (models.ExceptionFrame, bool) {
+	if m := goLocationRe.FindStringSubmatch(locationLine); m != nil {
+		if f := goFunctionRe.FindStringSubmatch(functionLine); f != nil {
+			module, function := splitGoFunction(f[1])
+			return models.ExceptionFrame{Function: function, File: m[1], Line: atoi(m[2]), Module: module}, true
+		}
+	}
+	if m := tracewayLocationRe.FindStringSubmatch(locationLine); m != nil && strings.HasSuffix(functionLine, ")") {
+		frame := models.ExceptionFrame{File: m[1], Line: atoi(m[2]), Column: atoi(m[3])}
+		function := strings.TrimSpace(functionLine)
+		if idx := strings.LastIndex(function, "("); idx >= 0 {
+			function = function[:idx]
+		}
+		if strings.HasSuffix(frame.File, ".go") {
+			frame.Module, frame.Function = splitGoFunction(function)
+		} else {
+			frame.Function = function
+		}
+		return frame, true
+	}
+	return models.ExceptionFrame{}, false
+}
+
+// parsePythonTraceback reads a traceback, Python prints the innermost call last and the exception after the frames
+func parsePythonTraceback(lines []string) models.ParsedStackTrace {
+	var parsed models.ParsedStackTrace
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.TrimSpace(line) == pythonTracebackHeader {
+			// chained exceptions repeat the traceback, the last one raised is kept
+			parsed.Frames = nil
+			continue
+		}
+		if m := pythonFrameRe.FindStringSubmatch(line); m != nil {
+			parsed.Frames = append(parsed.Frames, models.ExceptionFrame{Function: m[3], File: m[1], Line: atoi(m[2])})
+			continue
+		}
+		// source lines are indented below each frame
+		if line[0] != ' ' && line[0] != '\t' {
+			parsed.Type, parsed.Message = parseExceptionHeader(line)
+		}
+	}
+
+	for i, j := 0, len(parsed.Frames)-1; i < j; i, j = i+1, j-1 {
+		parsed.Frames[i], parsed.Frames[j] = parsed.Frames[j], parsed.Frames[i]
+	}
+	return parsed
+}
+
+func parseExceptionHeader(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "Uncaught ")
+	// Exception in thread "main" java.lang.IllegalStateException: closed
+	if strings.HasPrefix(line, "Exception in thread ") {
+		if _, rest, ok := strings.Cut(line, `" `); ok {
+			line = rest
+		}
+	}
+	if m := exceptionHeaderRe.FindStringSubmatch(line); m != nil {
+		return m[1], m[2]
+	}
+	if exceptionTypeRe.MatchString(line) {
+		return line, ""
+	}
+	return "", line
+}
+
+// splitGoFunction splits github.com/acme/api/handlers.(*Server).Handle into the package and the function
+func splitGoFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+1+dot+1:]
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// libraryPathMarkers are path fragments of dependencies and runtimes, frames in them aren't in-app by default
+var libraryPathMarkers = []string{
+	"node_modules/", "site-packages/", "dist-packages/", "/go/pkg/mod/", "/usr/local/go/src/", "/usr/lib/",
+	"<anonymous>", "[native code]", "webpack/bootstrap",
+}
+
+var libraryModulePrefixes = []string{"java.", "javax.", "jdk.", "sun.", "com.sun.", "kotlin.", "kotlinx.", "scala."}
+
+// MarkInAppFrames flags the frames of the project's own code. When prefixes are configured a frame is in-app if its
+// module or file starts with one of them, otherwise every frame outside dependencies and the standard library is.
+func MarkInAppFrames(frames []models.ExceptionFrame, prefixes []string) {
+	for i := range frames {
+		frames[i].InApp = isInAppFrame(frames[i], prefixes)
+	}
+}
+
+func isInAppFrame(frame models.ExceptionFrame, prefixes []string) bool {
+	if len(prefixes) > 0 {
+		for _, prefix := range prefixes {
+			if (frame.Module != "" && strings.HasPrefix(frame.Module, prefix)) || strings.HasPrefix(frame.File, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, marker := range libraryPathMarkers {
+		if strings.Contains(frame.File, marker) {
+			return false
+		}
+	}
+	for _, prefix := range libraryModulePrefixes {
+		if strings.HasPrefix(frame.Module, prefix) {
+			return false
+		}
+	}
+	// Go standard library packages have no domain in their first path element
+	if strings.HasSuffix(frame.File, ".go") && frame.Module != "" && frame.Module != "main" {
+		first, _, _ := strings.Cut(frame.Module, "/")
+		return strings.Contains(first, ".")
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"backend/app/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseStackTrace(t *testing.T) {
+	tests := []struct {
+		name       string
+		stackTrace string
+		expected   models.ParsedStackTrace
+	}{
+		{
+			name:       "traceway go sdk",
+			stackTrace: "*errors.errorString: connection refused\nhandleRequest()\n    handler.go:42\ngithub.com/acme/api/server.(*Server).Serve()\n    /app/server/server.go:128\n",
+			expected: models.ParsedStackTrace{
+				Type:    "*errors.errorString",
+				Message: "connection refused",
+				Frames: []models.ExceptionFrame{
+					{Function: "handleRequest", File: "handler.go", Line: 42},
+					{Function: "(*Server).Serve", File: "/app/server/server.go", Line: 128, Module: "github.com/acme/api/server"},
+				},
+			},
+		},
+		{
+			name:       "go panic",
+			stackTrace: "panic: runtime error: index out of range [5] with length 3\n\ngoroutine 1 [running]:\nmain.lookup(...)\n\t/app/main.go:12\nmain.main()\n\t/app/main.go:7 +0x1d\nexit status 2",
+			expected: models.ParsedStackTrace{
+				Type:    "panic",
+				Message: "runtime error: index out of range [5] with length 3",
+				Frames: []models.ExceptionFrame{
+					{Function: "lookup", File: "/app/main.go", Line: 12, Module: "main"},
+					{Function: "main", File: "/app/main.go", Line: 7, Module: "main"},
+				},
+			},
+		},
+		{
+			name:       "v8",
+			stackTrace: "TypeError: Cannot read properties of undefined (reading 'id')\n    at getUser (/app/src/users.js:10:15)\n    at async handler (/app/src/index.js:4:3)\n    at /app/node_modules/express/lib/router.js:284:7",
+			expected: models.ParsedStackTrace{
+				Type:    "TypeError",
+				Message: "Cannot read properties of undefined (reading 'id')",
+				Frames: []models.ExceptionFrame{
+					{Function: "getUser", File: "/app/src/users.js", Line: 10, Column: 15},
+					{Function: "handler", File: "/app/src/index.js", Line: 4, Column: 3},
+					{File: "/app/node_modules/express/lib/router.js", Line: 284, Column: 7},
+				},
+			},
+		},
+		{
+			name:       "spidermonkey",
+			stackTrace: "getUser@https://app.example.com/assets/main.js:10:15\n@https://app.example.com/assets/main.js:1:1",
+			expected: models.ParsedStackTrace{
+				Frames: []models.ExceptionFrame{
+					{Function: "getUser", File: "https://app.example.com/assets/main.js", Line: 10, Column: 15},
+					{File: "https://app.example.com/assets/main.js", Line: 1, Column: 1},
+				},
+			},
+		},
+		{
+			name:       "python",
+			stackTrace: "Traceback (most recent call last):\n  File \"/app/main.py\", line 3, in <module>\n    run()\n  File \"/app/jobs.py\", line 12, in run\n    int(value)\nValueError: invalid literal for int() with base 10: 'x'",
+			expected: models.ParsedStackTrace{
+				Type:    "ValueError",
+				Message: "invalid literal for int() with base 10: 'x'",
+				Frames: []models.ExceptionFrame{
+					{Function: "run", File: "/app/jobs.py", Line: 12},
+					{Function: "<module>", File: "/app/main.py", Line: 3},
+				},
+			},
+		},
+		{
+			name:       "java",
+			stackTrace: "Exception in thread \"main\" java.lang.IllegalStateException: closed\n\tat com.acme.Pool.acquire(Pool.java:42)\n\tat java.base/java.lang.Thread.run(Thread.java:833)\nCaused by: java.io.IOException: reset\n\tat com.acme.Socket.read(Socket.java:7)",
+			expected: models.ParsedStackTrace{
+				Type:    "java.lang.IllegalStateException",
+				Message: "closed",
+				Frames: []models.ExceptionFrame{
+					{Function: "acquire", File: "Pool.java", Line: 42, Module: "com.acme.Pool"},
+					{Function: "run", File: "Thread.java", Line: 833, Module: "java.lang.Thread"},
+				},
+			},
+		},
+		{
+			name:       "message without frames",
+			stackTrace: "something went wrong",
+			expected:   models.ParsedStackTrace{Message: "something went wrong"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseStackTrace(tt.stackTrace)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseStackTrace() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarkInAppFrames(t *testing.T) {
+	frames := []models.ExceptionFrame{
+		{File: "/app/src/users.js"},
+		{File: "/app/node_modules/express/lib/router.js"},
+		{File: "/usr/local/go/src/net/http/server.go", Module: "net/http"},
+		{File: "/app/server/server.go", Module: "github.com/acme/api/server"},
+		{File: "Thread.java", Module: "java.lang.Thread"},
+	}
+
+	tests := []struct {
+		name     string
+		prefixes []string
+		expected []bool
+	}{
+		{"defaults", nil, []bool{true, false, false, true, false}},
+		{"configured prefixes", []string{"/app/src/", "github.com/acme/"}, []bool{true, false, false, true, false}},
+		{"module prefix only", []string{"java."}, []bool{false, false, false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marked := append([]models.ExceptionFrame{}, frames...)
+			MarkInAppFrames(marked, tt.prefixes)
+			for i, frame := range marked {
+				if frame.InApp != tt.expected[i] {
+					t.Errorf("frame %s InApp = %v, want %v", frame.File, frame.InApp, tt.expected[i])
+				}
+			}
+		})
+	}
+}
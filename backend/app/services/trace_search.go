@@ -0,0 +1,69 @@
+package services
+
+import (
+	"backend/app/models"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseStatusCodeRange parses a status code filter: an exact code (404), a class (5xx) or a range (400-499)
+func ParseStatusCodeRange(value string) (models.StatusCodeRange, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if len(value) == 3 && strings.HasSuffix(value, "xx") && value[0] >= '1' && value[0] <= '5' {
+		class := int16(value[0]-'0') * 100
+		return models.StatusCodeRange{Min: class, Max: class + 99}, nil
+	}
+
+	if from, to, ok := strings.Cut(value, "-"); ok {
+		low, err := parseStatusCode(from)
+		if err != nil {
+			return models.StatusCodeRange{}, err
+		}
+		high, err := parseStatusCode(to)
+		if err != nil {
+			return models.StatusCodeRange{}, err
+		}
+		if low > high {
+			return models.StatusCodeRange{}, fmt.Errorf("invalid status code range %q", value)
+		}
+		return models.StatusCodeRange{Min: low, Max: high}, nil
+	}
+
+	code, err := parseStatusCode(value)
+	if err != nil {
+		return models.StatusCodeRange{}, err
+	}
+	return models.StatusCodeRange{Min: code, Max: code}, nil
+}
+
+func parseStatusCode(value string) (int16, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %q", value)
+	}
+	return int16(code), nil
+}
+
+// ValidateAttributeFilter checks the operator and that comparisons have a numeric value
+func ValidateAttributeFilter(filter models.AttributeFilter) error {
+	if filter.Key == "" {
+		return fmt.Errorf("attribute filter key is required")
+	}
+
+	switch filter.Operator {
+	case models.AttributeOperatorEquals, models.AttributeOperatorNotEquals,
+		models.AttributeOperatorContains, models.AttributeOperatorNotContains,
+		models.AttributeOperatorExists, models.AttributeOperatorNotExists:
+		return nil
+	case models.AttributeOperatorGreaterThan, models.AttributeOperatorGreaterOrEqual,
+		models.AttributeOperatorLessThan, models.AttributeOperatorLessOrEqual:
+		if _, err := strconv.ParseFloat(filter.Value, 64); err != nil {
+			return fmt.Errorf("attribute %q: operator %s needs a numeric value", filter.Key, filter.Operator)
+		}
+		return nil
+	default:
+		return fmt.Errorf("attribute %q: unknown operator %q", filter.Key, filter.Operator)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"backend/app/models"
+	"testing"
+)
+
+func TestParseStatusCodeRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected models.StatusCodeRange
+		wantErr  bool
+	}{
+		{"404", models.StatusCodeRange{Min: 404, Max: 404}, false},
+		{"5xx", models.StatusCodeRange{Min: 500, Max: 599}, false},
+		{"4XX", models.StatusCodeRange{Min: 400, Max: 499}, false},
+		{"400-429", models.StatusCodeRange{Min: 400, Max: 429}, false},
+		{"500-400", models.StatusCodeRange{}, true},
+		{"6xx", models.StatusCodeRange{}, true},
+		{"abc", models.StatusCodeRange{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseStatusCodeRange(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatusCodeRange(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseStatusCodeRange(%q) = %+v, want %+v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateAttributeFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  models.AttributeFilter
+		wantErr bool
+	}{
+		{"equals", models.AttributeFilter{Key: "tenant", Operator: "eq", Value: "acme"}, false},
+		{"exists without value", models.AttributeFilter{Key: "tenant", Operator: "exists"}, false},
+		{"numeric comparison", models.AttributeFilter{Key: "items", Operator: "gte", Value: "10"}, false},
+		{"comparison with text", models.AttributeFilter{Key: "items", Operator: "gt", Value: "many"}, true},
+		{"unknown operator", models.AttributeFilter{Key: "tenant", Operator: "like", Value: "a%"}, true},
+		{"missing key", models.AttributeFilter{Operator: "eq", Value: "acme"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAttributeFilter(tt.filter); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAttributeFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
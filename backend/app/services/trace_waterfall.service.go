@@ -0,0 +1,237 @@
+package services
+
+import (
+	"backend/app/models"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type traceNode struct {
+	entry    models.TraceEntry
+	children []*traceNode
+}
+
+// BuildTraceWaterfall arranges the endpoints, tasks and spans sharing a trace id into a waterfall.
+// Endpoints and tasks are the roots of their project. A root called by another service hangs under the span of
+// that service containing it in time, client spans first. A span hangs under its parent span when it was recorded,
+// otherwise under the root of its own project. Spans matching neither are grouped as orphans after the rest.
+func BuildTraceWaterfall(traceId uuid.UUID, endpoints []models.Endpoint, tasks []models.Task, spans []models.Span, exceptions []models.ExceptionStackTrace, projectNames map[uuid.UUID]string) *models.TraceWaterfall {
+	serviceName := func(name string, projectId uuid.UUID) string {
+		if name != "" {
+			return name
+		}
+		return projectNames[projectId]
+	}
+
+	var roots []*traceNode
+	for _, e := range endpoints {
+		roots = append(roots, &traceNode{entry: models.TraceEntry{
+			Id:          e.Id,
+			ProjectId:   e.ProjectId,
+			ProjectName: projectNames[e.ProjectId],
+			ServiceName: serviceName(e.ServerName, e.ProjectId),
+			Type:        models.TraceEntryTypeEndpoint,
+			Name:        e.Endpoint,
+			StartTime:   e.RecordedAt,
+			Duration:    e.Duration,
+			StatusCode:  e.StatusCode,
+		}})
+	}
+	for _, t := range tasks {
+		roots = append(roots, &traceNode{entry: models.TraceEntry{
+			Id:          t.Id,
+			ProjectId:   t.ProjectId,
+			ProjectName: projectNames[t.ProjectId],
+			ServiceName: serviceName(t.ServerName, t.ProjectId),
+			Type:        models.TraceEntryTypeTask,
+			Name:        t.TaskName,
+			StartTime:   t.RecordedAt,
+			Duration:    t.Duration,
+		}})
+	}
+	sortTraceNodes(roots)
+
+	rootByProject := make(map[uuid.UUID]*traceNode)
+	for _, r := range roots {
+		if _, ok := rootByProject[r.entry.ProjectId]; !ok {
+			rootByProject[r.entry.ProjectId] = r
+		}
+	}
+
+	spanNodes := make(map[uuid.UUID]*traceNode, len(spans))
+	for _, s := range spans {
+		spanNodes[s.Id] = &traceNode{entry: models.TraceEntry{
+			Id:          s.Id,
+			ProjectId:   s.ProjectId,
+			ProjectName: projectNames[s.ProjectId],
+			ServiceName: serviceName(s.ServiceName, s.ProjectId),
+			Type:        models.TraceEntryTypeSpan,
+			Kind:        s.Kind,
+			Name:        s.Name,
+			StartTime:   s.StartTime,
+			Duration:    s.Duration,
+		}}
+	}
+
+	var orphans []*traceNode
+	for _, s := range spans {
+		node := spanNodes[s.Id]
+
+		var parent *traceNode
+		if s.ParentId != nil && *s.ParentId != s.Id {
+			parent = spanNodes[*s.ParentId]
+		}
+		if parent == nil {
+			parent = rootByProject[s.ProjectId]
+		}
+
+		if parent == nil {
+			orphans = append(orphans, node)
+			continue
+		}
+		attachTraceNode(parent, node)
+	}
+
+	// endpoints and tasks don't record the span that called them, the caller is the tightest span of another service
+	// running around them. Only earlier roots are candidates so two roots can't end up under each other.
+	var topLevel []*traceNode
+	for i, r := range roots {
+		caller := findCallerSpan(r, spanNodes)
+		if caller == nil {
+			caller = findContainingNode(r, roots[:i])
+		}
+		if caller == nil {
+			topLevel = append(topLevel, r)
+			continue
+		}
+		attachTraceNode(caller, r)
+	}
+	allNodes := append([]*traceNode{}, roots...)
+	roots = topLevel
+	for _, n := range spanNodes {
+		allNodes = append(allNodes, n)
+	}
+
+	waterfall := &models.TraceWaterfall{
+		TraceId:    traceId,
+		Services:   []string{},
+		Entries:    []models.TraceEntry{},
+		Exceptions: exceptions,
+	}
+	if waterfall.Exceptions == nil {
+		waterfall.Exceptions = []models.ExceptionStackTrace{}
+	}
+
+	var end time.Time
+	extendBounds := func(entry models.TraceEntry) {
+		if waterfall.StartTime.IsZero() || entry.StartTime.Before(waterfall.StartTime) {
+			waterfall.StartTime = entry.StartTime
+		}
+		if entryEnd := entry.StartTime.Add(entry.Duration); entryEnd.After(end) {
+			end = entryEnd
+		}
+	}
+	for _, n := range allNodes {
+		extendBounds(n.entry)
+	}
+	waterfall.Duration = end.Sub(waterfall.StartTime)
+
+	visited := make(map[*traceNode]bool)
+	seenServices := make(map[string]bool)
+	var walk func(n *traceNode, depth int, orphan bool)
+	walk = func(n *traceNode, depth int, orphan bool) {
+		if visited[n] {
+			return
+		}
+		visited[n] = true
+
+		n.entry.Depth = depth
+		n.entry.Orphan = orphan
+		n.entry.Offset = n.entry.StartTime.Sub(waterfall.StartTime)
+		waterfall.Entries = append(waterfall.Entries, n.entry)
+		if n.entry.ServiceName != "" && !seenServices[n.entry.ServiceName] {
+			seenServices[n.entry.ServiceName] = true
+			waterfall.Services = append(waterfall.Services, n.entry.ServiceName)
+		}
+
+		sortTraceNodes(n.children)
+		for _, child := range n.children {
+			walk(child, depth+1, orphan)
+		}
+	}
+	for _, n := range roots {
+		walk(n, 0, false)
+	}
+
+	// entries whose parents reference each other are unreachable from the roots, they join the orphans
+	for _, n := range allNodes {
+		if !visited[n] && !slices.Contains(orphans, n) {
+			orphans = append(orphans, n)
+		}
+	}
+	sortTraceNodes(orphans)
+	for _, n := range orphans {
+		n.entry.ParentId = nil
+		walk(n, 0, true)
+	}
+
+	return waterfall
+}
+
+func attachTraceNode(parent, child *traceNode) {
+	parentId := parent.entry.Id
+	child.entry.ParentId = &parentId
+	parent.children = append(parent.children, child)
+}
+
+// findCallerSpan returns the span of another project containing the root in time, client spans before any other
+// kind and the tightest one among them
+func findCallerSpan(root *traceNode, spanNodes map[uuid.UUID]*traceNode) *traceNode {
+	var candidates []*traceNode
+	for _, n := range spanNodes {
+		if n.entry.ProjectId != root.entry.ProjectId {
+			candidates = append(candidates, n)
+		}
+	}
+	var clientSpans []*traceNode
+	for _, n := range candidates {
+		if n.entry.Kind == "client" {
+			clientSpans = append(clientSpans, n)
+		}
+	}
+	if caller := findContainingNode(root, clientSpans); caller != nil {
+		return caller
+	}
+	return findContainingNode(root, candidates)
+}
+
+// findContainingNode returns the shortest node of another project whose time range contains the given node,
+// the latest starting one on ties
+func findContainingNode(node *traceNode, candidates []*traceNode) *traceNode {
+	start := node.entry.StartTime
+	end := start.Add(node.entry.Duration)
+
+	var best *traceNode
+	for _, c := range candidates {
+		if c == node || c.entry.ProjectId == node.entry.ProjectId {
+			continue
+		}
+		if c.entry.StartTime.After(start) || c.entry.StartTime.Add(c.entry.Duration).Before(end) {
+			continue
+		}
+		if best == nil || c.entry.Duration < best.entry.Duration ||
+			(c.entry.Duration == best.entry.Duration && c.entry.StartTime.After(best.entry.StartTime)) {
+			best = c
+		}
+	}
+	return best
+}
+
+func sortTraceNodes(nodes []*traceNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].entry.StartTime.Before(nodes[j].entry.StartTime)
+	})
+}
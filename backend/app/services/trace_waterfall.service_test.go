@@ -0,0 +1,138 @@
+package services
+
+import (
+	"backend/app/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildTraceWaterfall(t *testing.T) {
+	traceId := uuid.New()
+	frontend := uuid.New()
+	backend := uuid.New()
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	clientSpan := uuid.New()
+	serverSpan := uuid.New()
+	querySpan := uuid.New()
+	unknownParent := uuid.New()
+
+	endpoints := []models.Endpoint{
+		{Id: traceId, ProjectId: frontend, Endpoint: "GET /checkout", RecordedAt: start, Duration: 100 * time.Millisecond, StatusCode: 200},
+	}
+	spans := []models.Span{
+		// recorded out of order, the waterfall sorts by start time
+		{Id: querySpan, TraceId: traceId, ProjectId: backend, Name: "SELECT orders", ServiceName: "orders", Kind: "client", StartTime: start.Add(30 * time.Millisecond), Duration: 20 * time.Millisecond, ParentId: &serverSpan},
+		{Id: serverSpan, TraceId: traceId, ProjectId: backend, Name: "GET /orders", ServiceName: "orders", Kind: "server", StartTime: start.Add(20 * time.Millisecond), Duration: 50 * time.Millisecond, ParentId: &clientSpan},
+		{Id: clientSpan, TraceId: traceId, ProjectId: frontend, Name: "HTTP GET orders", Kind: "client", StartTime: start.Add(10 * time.Millisecond), Duration: 70 * time.Millisecond, ParentId: &unknownParent},
+	}
+	projectNames := map[uuid.UUID]string{frontend: "web", backend: "orders-api"}
+
+	waterfall := BuildTraceWaterfall(traceId, endpoints, nil, spans, nil, projectNames)
+
+	expected := []struct {
+		id    uuid.UUID
+		depth int
+	}{
+		{traceId, 0},
+		{clientSpan, 1},
+		{serverSpan, 2},
+		{querySpan, 3},
+	}
+	if len(waterfall.Entries) != len(expected) {
+		t.Fatalf("got %d entries, want %d", len(waterfall.Entries), len(expected))
+	}
+	for i, e := range expected {
+		entry := waterfall.Entries[i]
+		if entry.Id != e.id || entry.Depth != e.depth {
+			t.Errorf("entry %d = %s (depth %d), want %s (depth %d)", i, entry.Name, entry.Depth, e.id, e.depth)
+		}
+	}
+
+	if parent := waterfall.Entries[1].ParentId; parent == nil || *parent != traceId {
+		t.Errorf("span with an unrecorded parent should hang under its project's root")
+	}
+	if waterfall.Entries[3].Offset != 30*time.Millisecond {
+		t.Errorf("offset = %v, want 30ms", waterfall.Entries[3].Offset)
+	}
+	if waterfall.Duration != 100*time.Millisecond {
+		t.Errorf("duration = %v, want 100ms", waterfall.Duration)
+	}
+	if len(waterfall.Services) != 2 || waterfall.Services[0] != "web" || waterfall.Services[1] != "orders" {
+		t.Errorf("services = %v, want [web orders]", waterfall.Services)
+	}
+}
+
+func TestBuildTraceWaterfallParentCycle(t *testing.T) {
+	traceId := uuid.New()
+	projectId := uuid.New()
+	a, b := uuid.New(), uuid.New()
+	start := time.Now()
+
+	spans := []models.Span{
+		{Id: a, ProjectId: projectId, StartTime: start, ParentId: &b},
+		{Id: b, ProjectId: projectId, StartTime: start.Add(time.Millisecond), ParentId: &a},
+	}
+
+	waterfall := BuildTraceWaterfall(traceId, nil, nil, spans, nil, nil)
+	if len(waterfall.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(waterfall.Entries))
+	}
+}
+
+func TestBuildTraceWaterfallMultipleServices(t *testing.T) {
+	traceId := uuid.New()
+	frontend := uuid.New()
+	backend := uuid.New()
+	worker := uuid.New()
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	backendEndpoint := uuid.New()
+	clientSpan := uuid.New()
+	renderSpan := uuid.New()
+	querySpan := uuid.New()
+	orphanSpan := uuid.New()
+	missingParent := uuid.New()
+
+	endpoints := []models.Endpoint{
+		{Id: traceId, ProjectId: frontend, Endpoint: "GET /checkout", RecordedAt: start, Duration: 100 * time.Millisecond},
+		{Id: backendEndpoint, ProjectId: backend, Endpoint: "GET /orders", RecordedAt: start.Add(15 * time.Millisecond), Duration: 50 * time.Millisecond},
+	}
+	spans := []models.Span{
+		// the render span also contains the backend endpoint, the client span is the caller
+		{Id: renderSpan, ProjectId: frontend, Name: "render", Kind: "internal", StartTime: start.Add(5 * time.Millisecond), Duration: 65 * time.Millisecond},
+		{Id: clientSpan, ProjectId: frontend, Name: "HTTP GET orders", Kind: "client", StartTime: start.Add(10 * time.Millisecond), Duration: 70 * time.Millisecond, ParentId: &renderSpan},
+		{Id: querySpan, ProjectId: backend, Name: "SELECT orders", Kind: "client", StartTime: start.Add(20 * time.Millisecond), Duration: 10 * time.Millisecond},
+		{Id: orphanSpan, ProjectId: worker, Name: "send email", Kind: "internal", StartTime: start.Add(30 * time.Millisecond), Duration: 5 * time.Millisecond, ParentId: &missingParent},
+	}
+
+	waterfall := BuildTraceWaterfall(traceId, endpoints, nil, spans, nil, nil)
+
+	expected := []struct {
+		id     uuid.UUID
+		depth  int
+		parent *uuid.UUID
+		orphan bool
+	}{
+		{traceId, 0, nil, false},
+		{renderSpan, 1, &traceId, false},
+		{clientSpan, 2, &renderSpan, false},
+		{backendEndpoint, 3, &clientSpan, false},
+		{querySpan, 4, &backendEndpoint, false},
+		{orphanSpan, 0, nil, true},
+	}
+	if len(waterfall.Entries) != len(expected) {
+		t.Fatalf("got %d entries, want %d", len(waterfall.Entries), len(expected))
+	}
+	for i, e := range expected {
+		entry := waterfall.Entries[i]
+		if entry.Id != e.id || entry.Depth != e.depth || entry.Orphan != e.orphan {
+			t.Errorf("entry %d = %s (depth %d, orphan %v), want %s (depth %d, orphan %v)", i, entry.Name, entry.Depth, entry.Orphan, e.id, e.depth, e.orphan)
+		}
+		if (entry.ParentId == nil) != (e.parent == nil) || (e.parent != nil && *entry.ParentId != *e.parent) {
+			t.Errorf("entry %d (%s) parent = %v, want %v", i, entry.Name, entry.ParentId, e.parent)
+		}
+	}
+}
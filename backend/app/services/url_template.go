@@ -0,0 +1,52 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	urlUUIDSegmentRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	urlHexSegmentRegex  = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	// long mixed tokens such as API keys or base64 ids, plain words never contain digits
+	urlTokenSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_\-=]{20,}$`)
+)
+
+// TemplateURLPath turns a concrete request path into a route template by replacing id-like segments with {id},
+// e.g. /v1/customers/1234/charges?limit=10 becomes /v1/customers/{id}/charges. Used for outgoing calls
+// when the instrumentation doesn't report url.template.
+func TemplateURLPath(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isIdSegment(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isIdSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if isNumeric(segment) || urlUUIDSegmentRegex.MatchString(segment) || urlHexSegmentRegex.MatchString(segment) {
+		return true
+	}
+	return urlTokenSegmentRegex.MatchString(segment) && strings.IndexFunc(segment, func(r rune) bool { return r >= '0' && r <= '9' }) >= 0
+}
+
+func isNumeric(segment string) bool {
+	for i := 0; i < len(segment); i++ {
+		if !isDigit(segment[i]) {
+			return false
+		}
+	}
+	return true
+}
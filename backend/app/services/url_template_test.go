@@ -0,0 +1,26 @@
+package services
+
+import "testing"
+
+func TestTemplateURLPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"numeric id and query string", "/v1/customers/1234/charges?limit=10", "/v1/customers/{id}/charges"},
+		{"uuid", "/orders/3f2a9c1e-8b7d-4e6f-a5c4-1d2e3f4a5b6c", "/orders/{id}"},
+		{"long hex", "/blobs/9f86d081884c7d659a2feaa0c55ad015", "/blobs/{id}"},
+		{"token with digits", "/v1/payment_intents/pi_3MtwBwLkdIwHu7ix28a3tqPa", "/v1/payment_intents/{id}"},
+		{"plain words are kept", "/api/v2/users/search", "/api/v2/users/search"},
+		{"empty path", "", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TemplateURLPath(tt.path); got != tt.expected {
+				t.Errorf("TemplateURLPath(%q) = %q, want %q", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
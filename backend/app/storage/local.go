@@ -45,3 +45,11 @@ func (l *localStorage) Read(_ context.Context, key string) ([]byte, error) {
 	}
 	return data, nil
 }
+
+func (l *localStorage) Delete(_ context.Context, key string) error {
+	fullPath := filepath.Join(l.basePath, key)
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file %s: %w", fullPath, err)
+	}
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 type Storage interface {
 	Write(ctx context.Context, key string, data []byte) error
 	Read(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the file at key, deleting a missing file is not an error
+	Delete(ctx context.Context, key string) error
 }
 
 var Store Storage
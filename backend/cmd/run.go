@@ -4,6 +4,7 @@ import (
 	"backend/app/cache"
 	"backend/app/chdb"
 	"backend/app/controllers"
+	"backend/app/jobs"
 	"backend/app/middleware"
 	"backend/app/migrations"
 	"backend/app/models"
@@ -68,9 +69,13 @@ func Run() {
 	middleware.InitRequireProjectAccess()
 	middleware.InitRequireAdminAccess()
 	middleware.InitUseSourceMapAuth()
+	middleware.InitUseReleaseAuth()
 
 	services.InitEmail()
 
+	jobs.StartServiceMapAggregation(ctx)
+	jobs.StartSourceMapResolution(ctx)
+
 	for _, hook := range PostStartupHooks {
 		hook(ctx)
 	}